@@ -4,17 +4,9 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
-
 	"github.com/asafdavid23/eolctl/internal/logging"
+	"github.com/asafdavid23/eolctl/pkg/report"
 
-	localCache "github.com/asafdavid23/eolctl/internal/cache"
-	helpers "github.com/asafdavid23/eolctl/pkg/helpers"
-
-	"github.com/olekukonko/tablewriter"
-	"github.com/patrickmn/go-cache"
 	"github.com/spf13/cobra"
 )
 
@@ -23,90 +15,43 @@ var availableProductsCmd = &cobra.Command{
 	Use:   "available-products",
 	Short: "List all products supported by the API.",
 	Long: `The 'available-products' command retrieves and displays a list of all products currently supported by the API. 
-You can filter the list to find relevant products that meet your specific needs, allowing you to quickly identify which products are available for interaction with the API.`,
+You can filter the list to find relevant products that meet your specific needs, allowing you to quickly identify which products are available for interaction with the API.
+
+Supports every output type.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var outputData []byte
-		var err error
-		var products []json.RawMessage
+		var products []string
 
 		logLevel, _ := cmd.Flags().GetString("log-level")
 		output, _ := cmd.Flags().GetString("output")
 
 		logger := logging.NewLogger(logLevel)
+		if err := report.Validate(output); err != nil {
+			logger.Fatalf("%v", err)
+		}
 
-		c, err := localCache.InitializeCacheFile()
-
+		provider, err := newEOLProvider(logger)
 		if err != nil {
-			logger.Fatalf("Failed to initialize cache file: %v", err)
+			logger.Fatalf("Failed to configure endoflife.date client: %v", err)
 		}
 
-		cacheKey := "available-products"
-
-		if cacheData, found := c.Get(cacheKey); found {
-			logger.Info("Cache hit for available products")
-
-			cacheDataBytes, ok := cacheData.([]byte)
-			if !ok {
-				logger.Fatalf("Failed to assert cache data to []byte")
-			}
-
-			// Parse API response into products slice
-			if err := json.Unmarshal(cacheDataBytes, &products); err != nil {
-				logger.Fatalf("Failed to parse JSON response: %v", err)
-			}
-
-		} else {
-			logger.Info("Cache miss for available products")
-			logger.Info("Fetching available products from the API")
-			outputData, err = helpers.GetAvailableProducts(output)
-
-			if err != nil {
-				logger.Fatalf("Failed to fetch available products from the API: %v", err)
-			}
-
-			// Parse API response into products slice
-			if err := json.Unmarshal(outputData, &products); err != nil {
-				logger.Fatalf("Failed to parse JSON response: %v", err)
-			}
-
-			logger.Debug("Caching available products")
-			c.Set(cacheKey, outputData, cache.DefaultExpiration)
-			localCache.SaveCacheFile()
+		logger.Debug("Fetching available products from the API")
+		products, err = provider.GetAvailableProducts(cmd.Context())
+		if err != nil {
+			logger.Fatalf("Failed to fetch available products from the API: %v", err)
 		}
 
-		if output == "table" {
-			var data []string
-
-			for _, product := range products {
-				var productData string
-
-				if err := json.Unmarshal(product, &productData); err != nil {
-					logger.Fatalf("Failed to parse product data: %v", err)
-				}
-				data = append(data, productData)
-			}
-
-			headers := []string{"Product"}
-
-			table := tablewriter.NewWriter(os.Stdout)
-			table.SetHeader(headers)
-
-			for _, row := range data {
-				rowData := []string{row}
-				table.Append(rowData)
-			}
-
-			table.Render()
-		} else if output == "json" {
-			productsJSON, err := json.Marshal(products)
-
-			if err != nil {
-				logger.Fatalf("Failed to marshal products to JSON: %v", err)
-			}
+		rows := report.Rows{Header: []string{"Product"}}
+		for _, product := range products {
+			rows.Data = append(rows.Data, []string{product})
+		}
 
-			fmt.Print(string(productsJSON))
-		} else {
-			logger.Fatal("Output type is not valid.")
+		out, err := openOutput(output, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		defer out.Close()
+		if err := report.Render(out, output, newListing("Available products", products, rows)); err != nil {
+			logger.Fatalf("%v", err)
 		}
 	},
 }
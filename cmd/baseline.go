@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/asafdavid23/eolctl/pkg/baseline"
+	"github.com/asafdavid23/eolctl/pkg/report"
+)
+
+// addBaselineFlags registers the baseline flags on a scan command.
+func addBaselineFlags(cmd *cobra.Command) {
+	cmd.Flags().String("baseline", "", "Report only findings that are new or worse than in this baseline file")
+	cmd.Flags().String("write-baseline", "", "Record the current findings in this baseline file")
+}
+
+// baselineFilter drops findings already recorded in a baseline and collects
+// every finding for --write-baseline.
+type baselineFilter struct {
+	previous *baseline.Baseline
+	// failRank is the rank of the --fail-on level, or -1.
+	failRank   int
+	writePath  string
+	findings   []baseline.Finding
+	suppressed int
+}
+
+// newBaselineFilter reads the baseline flags. Components that were UNKNOWN
+// in the baseline are reported once rated at or above the gate's level.
+func newBaselineFilter(cmd *cobra.Command, gate *failGate) (*baselineFilter, error) {
+	f := &baselineFilter{failRank: gate.rank}
+	f.writePath, _ = cmd.Flags().GetString("write-baseline")
+	if path, _ := cmd.Flags().GetString("baseline"); path != "" {
+		b, err := baseline.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		f.previous = b
+	}
+	return f, nil
+}
+
+// keep records finding and reports whether it should be shown: without a
+// baseline everything is, otherwise only new or worsened findings, which
+// are marked with their status. Waived findings are compared by the risk
+// the waiver accepted.
+func (f *baselineFilter) keep(r *report.Finding) bool {
+	risk := r.Risk
+	if r.AcceptedRisk != "" {
+		risk = r.AcceptedRisk
+	}
+	finding := baseline.Finding{
+		ID:        findingID(*r),
+		Product:   r.Product,
+		Version:   r.DisplayVersion(),
+		Risk:      risk,
+		PatchRisk: r.PatchRisk,
+	}
+	f.findings = append(f.findings, finding)
+	if f.previous == nil {
+		return true
+	}
+	status := f.previous.Compare(finding, riskPolicy.Rank, f.failRank)
+	if status == baseline.StatusKnown {
+		f.suppressed++
+		return false
+	}
+	r.Baseline = string(status)
+	return true
+}
+
+// finish writes the baseline if requested and logs how many findings the
+// baseline suppressed.
+func (f *baselineFilter) finish(logger *log.Logger) {
+	if f.previous != nil {
+		logger.Infof("Baseline: %d known finding(s) suppressed, %d new or worsened", f.suppressed, len(f.findings)-f.suppressed)
+	}
+	if f.writePath == "" {
+		return
+	}
+	if err := baseline.New(f.findings, time.Now()).Write(f.writePath); err != nil {
+		logger.Fatalf("%v", err)
+	}
+	logger.Infof("Wrote %d finding(s) to baseline %s", len(f.findings), f.writePath)
+}
@@ -0,0 +1,328 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	localCache "github.com/asafdavid23/eolctl/internal/cache"
+	"github.com/asafdavid23/eolctl/internal/logging"
+	"github.com/asafdavid23/eolctl/pkg/report"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the local API response cache.",
+	Long: `The 'cache' command shows what eolctl has cached, how old each entry is and when it expires,
+and lets you remove individual entries or purge the cache when a lookup returns surprising data.`,
+	Run: func(cmd *cobra.Command, args []string) {
+	},
+}
+
+var cachePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of the cache file.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		logger := logging.NewLogger(logLevel)
+
+		store := initCache(logger)
+		fmt.Println(store.Location())
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show entry counts, sizes and ages for the cache.",
+	Long: `The 'stats' command shows how many entries the cache holds, their total size, and the oldest and
+newest entry. Supports every output type.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		output, _ := cmd.Flags().GetString("output")
+		logger := logging.NewLogger(logLevel)
+		if err := report.Validate(output); err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		store := initCache(logger)
+		infos, err := store.List()
+		if err != nil {
+			logger.Fatalf("Failed to list cache entries: %v", err)
+		}
+
+		stats := struct {
+			Path      string     `json:"path"`
+			FileSize  int64      `json:"file_size"`
+			Entries   int        `json:"entries"`
+			Fresh     int        `json:"fresh"`
+			Stale     int        `json:"stale"`
+			TotalSize int        `json:"total_size"`
+			Oldest    *time.Time `json:"oldest,omitempty"`
+			Newest    *time.Time `json:"newest,omitempty"`
+		}{Path: store.Location()}
+
+		stats.FileSize = cacheFileSize(store)
+
+		var oldest, newest time.Time
+		now := time.Now()
+		for _, info := range infos {
+			stats.Entries++
+			stats.TotalSize += info.Size
+			if info.Stale(now) {
+				stats.Stale++
+			} else {
+				stats.Fresh++
+			}
+			if info.StoredAt.IsZero() {
+				continue
+			}
+			if oldest.IsZero() || info.StoredAt.Before(oldest) {
+				oldest = info.StoredAt
+			}
+			if info.StoredAt.After(newest) {
+				newest = info.StoredAt
+			}
+		}
+		if !oldest.IsZero() {
+			stats.Oldest, stats.Newest = &oldest, &newest
+		}
+
+		rows := report.Rows{}
+		add := func(name, value string) {
+			rows.Data = append(rows.Data, []string{name, value})
+		}
+		add("Location", stats.Path)
+		if stats.FileSize > 0 {
+			add("File size", formatBytes(int(stats.FileSize)))
+		}
+		add("Entries", strconv.Itoa(stats.Entries))
+		add("Fresh", strconv.Itoa(stats.Fresh))
+		add("Stale", strconv.Itoa(stats.Stale))
+		add("Cached data", formatBytes(stats.TotalSize))
+		if stats.Oldest != nil {
+			add("Oldest entry", formatAge(now.Sub(oldest))+" ago")
+			add("Newest entry", formatAge(now.Sub(newest))+" ago")
+		}
+
+		out, err := openOutput(output, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		defer out.Close()
+		if err := report.Render(out, output, newListing("Cache statistics", stats, rows)); err != nil {
+			logger.Fatalf("%v", err)
+		}
+	},
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached entries with their size, age and expiration.",
+	Long: `The 'list' command lists every cached entry with its size, age, status and expiration.
+Supports every output type.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		output, _ := cmd.Flags().GetString("output")
+		logger := logging.NewLogger(logLevel)
+		if err := report.Validate(output); err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		store := initCache(logger)
+		infos, err := store.List()
+		if err != nil {
+			logger.Fatalf("Failed to list cache entries: %v", err)
+		}
+
+		now := time.Now()
+		rows := report.Rows{Header: []string{"Key", "Size", "Age", "Status", "Expires"}}
+		for _, info := range infos {
+			rows.Data = append(rows.Data, []string{
+				info.Key,
+				formatBytes(info.Size),
+				entryAge(info, now),
+				entryStatus(info, now),
+				entryExpiry(info, now),
+			})
+		}
+
+		out, err := openOutput(output, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		defer out.Close()
+		if err := report.Render(out, output, newListing("Cache entries", infos, rows)); err != nil {
+			logger.Fatalf("%v", err)
+		}
+	},
+}
+
+var cacheShowCmd = &cobra.Command{
+	Use:   "show <key>",
+	Short: "Print a cached entry and its metadata.",
+	Long: `The 'show' command prints the cached response body for a key as listed by 'eolctl cache list'.
+Metadata is written to stderr so the body can be piped to other tools.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		logger := logging.NewLogger(logLevel)
+
+		store := initCache(logger)
+
+		key := args[0]
+		entry, found, err := store.Get(key)
+		if err != nil {
+			logger.Fatalf("Failed to read cache entry: %v", err)
+		}
+		if !found {
+			logger.Fatalf("No cache entry for %q", key)
+		}
+
+		now := time.Now()
+		info := localCache.Info{Key: key, StoredAt: entry.StoredAt, FreshUntil: entry.FreshUntil, ExpiresAt: entry.ExpiresAt, Size: len(entry.Body)}
+		fmt.Fprintf(os.Stderr, "Key:         %s\n", key)
+		fmt.Fprintf(os.Stderr, "Status:      %d, %s (%s)\n", entry.StatusCode, entryStatus(info, now), formatBytes(info.Size))
+		fmt.Fprintf(os.Stderr, "Stored:      %s (%s ago)\n", entry.StoredAt.Format(time.RFC3339), formatAge(now.Sub(entry.StoredAt)))
+		fmt.Fprintf(os.Stderr, "Fresh until: %s\n", entry.FreshUntil.Format(time.RFC3339))
+		if entry.ETag != "" {
+			fmt.Fprintf(os.Stderr, "ETag:        %s\n", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			fmt.Fprintf(os.Stderr, "Modified:    %s\n", entry.LastModified)
+		}
+
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, entry.Body, "", "  "); err == nil {
+			fmt.Println(pretty.String())
+		} else {
+			fmt.Println(string(entry.Body))
+		}
+	},
+}
+
+var cachePurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Remove entries from the cache.",
+	Long: `The 'purge' command removes every cached entry. Use --stale to only drop entries past their
+freshness window, including those still served while they are revalidated in the background, so the
+next run fetches them again. Use --key to drop a single entry.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		stale, _ := cmd.Flags().GetBool("stale")
+		key, _ := cmd.Flags().GetString("key")
+		logger := logging.NewLogger(logLevel)
+
+		if stale && key != "" {
+			logger.Fatal("--stale can't be combined with --key")
+		}
+
+		store := initCache(logger)
+
+		var (
+			removed int
+			err     error
+		)
+		switch {
+		case key != "":
+			var found bool
+			found, err = store.Delete(key)
+			if err == nil && !found {
+				logger.Fatalf("No cache entry for %q", key)
+			}
+			removed = 1
+		case stale:
+			removed, err = localCache.PurgeStale(store)
+		default:
+			removed, err = store.Clear()
+		}
+		if err != nil {
+			logger.Fatalf("Failed to purge cache: %v", err)
+		}
+
+		if err := closeCacheStore(); err != nil {
+			logger.Fatalf("Failed to save cache: %v", err)
+		}
+		fmt.Printf("Purged %d cache entries\n", removed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cachePathCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheShowCmd)
+	cacheCmd.AddCommand(cachePurgeCmd)
+
+	cachePurgeCmd.Flags().Bool("stale", false, "Only remove entries past their freshness window, including those served while revalidating")
+	cachePurgeCmd.Flags().String("key", "", "Remove a single entry")
+}
+
+func initCache(logger *log.Logger) localCache.Store {
+	store, err := openCacheStore(logger)
+	if err != nil {
+		logger.Fatalf("Failed to open cache: %v", err)
+	}
+	return store
+}
+
+// cacheFileSize returns the size of the file backing store, or 0 for
+// backends without one, such as redis whose location is a URL.
+func cacheFileSize(store localCache.Store) int64 {
+	switch store.(type) {
+	case *localCache.FileStore, *localCache.BoltStore:
+		if fi, err := os.Stat(store.Location()); err == nil {
+			return fi.Size()
+		}
+	}
+	return 0
+}
+
+func entryAge(info localCache.Info, now time.Time) string {
+	if info.StoredAt.IsZero() {
+		return "unknown"
+	}
+	return formatAge(now.Sub(info.StoredAt))
+}
+
+func entryStatus(info localCache.Info, now time.Time) string {
+	if info.Stale(now) {
+		return "stale"
+	}
+	return "fresh for " + formatAge(info.FreshUntil.Sub(now))
+}
+
+func entryExpiry(info localCache.Info, now time.Time) string {
+	if info.ExpiresAt.IsZero() {
+		return "never"
+	}
+	return "in " + formatAge(info.ExpiresAt.Sub(now))
+}
+
+// formatBytes renders a byte count using binary units.
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/asafdavid23/eolctl/internal/logging"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+	"github.com/asafdavid23/eolctl/pkg/helpers"
+	"github.com/asafdavid23/eolctl/pkg/report"
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+// calendarCmd represents the calendar command
+var calendarCmd = &cobra.Command{
+	Use:   "calendar PRODUCT[@VERSION]...",
+	Short: "Export EOL and end-of-support dates as an iCalendar feed.",
+	Long: `The 'calendar' command writes the EOL, active support and extended support end dates of the given
+products as iCalendar (.ics) events, with reminder alarms before each date. Give PRODUCT to include every
+cycle of a product, or PRODUCT@VERSION for the cycle of one version.
+
+Event UIDs are stable, so importing an updated feed updates existing events instead of duplicating them.
+With --serve the feed is served over HTTP and rebuilt on every request, so calendar applications can
+subscribe to it:
+
+  eolctl calendar nodejs@20 python@3.12 --serve :8080
+
+Scan results can be exported the same way with 'eolctl scan project -o ics'.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		addr, _ := cmd.Flags().GetString("serve")
+		logger := logging.NewLogger(logLevel)
+
+		ctx := cmd.Context()
+		client, err := newEOLProvider(logger)
+		if err != nil {
+			logger.Fatalf("Failed to configure endoflife.date client: %v", err)
+		}
+		resolver, err := newResolver(ctx, client)
+		if err != nil {
+			logger.Fatalf("Failed to fetch available products from the API: %v", err)
+		}
+		var specs []calendarSpec
+		for _, arg := range args {
+			name, version, _ := strings.Cut(arg, "@")
+			specs = append(specs, calendarSpec{name: resolveProductName(resolver, name, logger), version: version})
+		}
+
+		feed, err := newCalendarFeed(client, specs, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		if addr == "" {
+			data, err := feed.render(ctx)
+			if err != nil {
+				logger.Fatalf("%v", err)
+			}
+			out, err := openOutput(report.ICS, logger)
+			if err != nil {
+				logger.Fatalf("%v", err)
+			}
+			defer out.Close()
+			if _, err := out.Write(data); err != nil {
+				logger.Fatalf("%v", err)
+			}
+			return
+		}
+
+		if err := serveCalendar(ctx, addr, feed, logger); err != nil {
+			logger.Fatalf("Calendar server failed: %v", err)
+		}
+	},
+}
+
+// calendarSaveInterval is how often --serve persists the cache.
+const calendarSaveInterval = 5 * time.Minute
+
+// serveCalendar serves feed on addr until SIGINT or SIGTERM, saving the
+// cache periodically so a long-running server keeps its responses.
+func serveCalendar(ctx context.Context, addr string, feed *calendarFeed, logger *log.Logger) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		data, err := feed.render(r.Context())
+		if err != nil {
+			logger.Errorf("Failed to build calendar: %v", err)
+			http.Error(w, "failed to build calendar", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="eolctl.ics"`)
+		w.Write(data)
+	})
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		ticker := time.NewTicker(calendarSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				server.Shutdown(shutdownCtx)
+				return
+			case <-ticker.C:
+				if err := saveCacheStore(); err != nil {
+					logger.Warnf("Failed to save cache file: %v", err)
+				}
+			}
+		}
+	}()
+
+	logger.Infof("Serving calendar on http://%s/", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logger.Info("Calendar server stopped")
+	return nil
+}
+
+// calendarSpec is a product, and optionally a version, to put in the
+// calendar.
+type calendarSpec struct {
+	name    string
+	version string
+}
+
+// calendarFeed builds the iCalendar feed of specs. The policy and waivers
+// are loaded once; cycles and the clock are read on every render.
+type calendarFeed struct {
+	client    endoflife.Provider
+	specs     []calendarSpec
+	evaluator helpers.Evaluator
+	waivers   *waiver.Set
+
+	// mu serialises renders, since matching waivers records which matched.
+	mu sync.Mutex
+}
+
+func newCalendarFeed(client endoflife.Provider, specs []calendarSpec, logger *log.Logger) (*calendarFeed, error) {
+	evaluator, err := newEvaluator(logger)
+	if err != nil {
+		return nil, err
+	}
+	waivers, err := loadWaivers("", logger)
+	if err != nil {
+		return nil, err
+	}
+	return &calendarFeed{client: client, specs: specs, evaluator: evaluator, waivers: waivers}, nil
+}
+
+// render rates the cycles of the feed's products and renders them as
+// iCalendar.
+func (c *calendarFeed) render(ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := evaluationTime()
+	var findings []report.Finding
+	for _, spec := range c.specs {
+		cycles, err := calendarCycles(ctx, c.client, spec)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, productFindings(spec.name, spec.version, cycles, c.evaluator, c.waivers, now)...)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, report.ICS, newReport(report.SourceProduct, findings)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// calendarCycles returns the cycle of spec's version, or every cycle of the
+// product when no version is given.
+func calendarCycles(ctx context.Context, client endoflife.Provider, spec calendarSpec) ([]endoflife.Cycle, error) {
+	if spec.version == "" {
+		return client.GetProduct(ctx, spec.name)
+	}
+	cycle, err := endoflife.FindCycle(ctx, client, spec.name, spec.version)
+	if err != nil {
+		return nil, err
+	}
+	return []endoflife.Cycle{*cycle}, nil
+}
+
+// calendarReminders returns the configured alarm days for ics output.
+func calendarReminders() []int {
+	return viper.GetIntSlice("calendar.reminders")
+}
+
+func init() {
+	rootCmd.AddCommand(calendarCmd)
+
+	calendarCmd.Flags().String("serve", "", "Serve the calendar over HTTP on this address (e.g. :8080) instead of printing it")
+	calendarCmd.Flags().IntSlice("reminders", report.DefaultReminders, "Days before each date to raise a reminder alarm")
+
+	viper.BindPFlag("calendar.reminders", calendarCmd.Flags().Lookup("reminders"))
+}
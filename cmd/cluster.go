@@ -1,30 +1,22 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/asafdavid23/eolctl/internal/logging"
 	ai "github.com/asafdavid23/eolctl/pkg/ai"
 	"github.com/asafdavid23/eolctl/pkg/artifacthub"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
 	"github.com/asafdavid23/eolctl/pkg/helm"
-	helpers "github.com/asafdavid23/eolctl/pkg/helpers"
-	"github.com/olekukonko/tablewriter"
+	"github.com/asafdavid23/eolctl/pkg/policy"
+	"github.com/asafdavid23/eolctl/pkg/report"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
-type ClusterReleaseInfo struct {
-	Release      string `json:"release"`
-	Namespace    string `json:"namespace"`
-	Chart        string `json:"chart"`
-	Product      string `json:"product"`
-	Version      string `json:"version"`
-	Eol          string `json:"eol"`
-	Risk         string `json:"risk"`
-	DaysUntilEOL int    `json:"days_until_eol,omitempty"`
-}
+var errOfflineFallback = errors.New("ArtifactHub is not available in offline mode")
 
 // chartToSlug strips the trailing version suffix from a chart name.
 // e.g., "cert-manager-v1.12.2" → "cert-manager", "nginx-ingress-4.7.1" → "nginx-ingress"
@@ -49,6 +41,10 @@ each chart's app version against the endoflife.date API to report EOL status and
 		logLevel, _ := cmd.Flags().GetString("log-level")
 		logger := logging.NewLogger(logLevel)
 		output, _ := cmd.Flags().GetString("output")
+		if err := report.Validate(output); err != nil {
+			logger.Fatalf("%v", err)
+		}
+		offline := viper.GetBool("offline.enabled")
 
 		logger.Debug("Listing Helm releases from cluster")
 		releases, err := helm.ListReleases()
@@ -65,7 +61,7 @@ each chart's app version against the endoflife.date API to report EOL status and
 		logger.Debug("Using Claude to map Helm charts to endoflife.date product slugs")
 		stacks, err := ai.DetectHelmEOL(releases)
 		if err != nil {
-			logger.Fatalf("failed to detect Helm EOL info: %v", err)
+			logger.Warnf("Claude chart mapping unavailable, resolving chart names locally: %v", err)
 		}
 		logger.Debugf("Mapped %d chart(s) to product slugs", len(stacks))
 
@@ -91,114 +87,130 @@ each chart's app version against the endoflife.date API to report EOL status and
 			}
 		}
 
-		var results []ClusterReleaseInfo
+		client, err := newEOLProvider(logger)
+		if err != nil {
+			logger.Fatalf("failed to configure endoflife.date client: %v", err)
+		}
+		ahClient, err := newArtifactHubClient(logger)
+		if err != nil {
+			logger.Fatalf("failed to configure ArtifactHub client: %v", err)
+		}
+
+		// Check every slug against the known products: aliases and near
+		// misses are corrected, and a chart name that resolves cleanly wins
+		// over a slug endoflife.date does not know.
+		resolver, err := newResolver(cmd.Context(), client)
+		if err != nil {
+			logger.Warnf("failed to fetch available products, skipping product name resolution: %v", err)
+		}
+		for i := range stacks {
+			if resolver == nil {
+				break
+			}
+			for _, candidate := range []string{stacks[i].Language, chartToSlug(releaseByName[stacks[i].ReleaseName].Chart)} {
+				if product, ok := resolver.Best(candidate); ok {
+					if product != stacks[i].Language {
+						logger.Debugf("Resolved %q to product %q for release %s", stacks[i].Language, product, stacks[i].ReleaseName)
+					}
+					stacks[i].Language = product
+					break
+				}
+			}
+		}
+
+		evaluator, err := newEvaluator(logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		gate, err := newFailGate(cmd)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		waivers, err := loadWaivers("", logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		now := evaluationTime()
+		filter, err := newBaselineFilter(cmd, gate)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		var findings []report.Finding
 
 		for _, stack := range stacks {
 			orig := releaseByName[stack.ReleaseName]
 
-			var resultMap map[string]interface{}
-			productData, err := helpers.GetProduct(stack.Language, stack.Version)
+			// Match the release's own app version rather than Claude's
+			// rendering of it; the matcher finds the cycle deterministically.
+			version := stack.Version
+			if orig.AppVersion != "" {
+				version = orig.AppVersion
+			}
+
+			scope := policy.Scope{Product: stack.Language, Namespace: orig.Namespace}
+			finding := report.Finding{
+				Source:    report.SourceCluster,
+				Location:  orig.Namespace + "/" + stack.ReleaseName,
+				Namespace: orig.Namespace,
+				Release:   stack.ReleaseName,
+				Chart:     orig.Chart,
+				Product:   stack.Language,
+				Version:   version,
+			}
+
+			cycle, err := endoflife.FindCycle(cmd.Context(), client, stack.Language, version)
+			if errors.Is(err, endoflife.ErrNotFound) && version != stack.Version && stack.Version != "" {
+				cycle, err = endoflife.FindCycle(cmd.Context(), client, stack.Language, stack.Version)
+			}
 			if err != nil {
 				// endoflife.date doesn't know this product — try ArtifactHub
-				logger.Debugf("endoflife.date lookup failed for %s, trying ArtifactHub fallback", stack.Language)
-				pkg, ahErr := artifacthub.SearchPackage(stack.Language)
+				var pkg *artifacthub.Package
+				ahErr := errOfflineFallback
+				if !offline {
+					logger.Debugf("endoflife.date lookup failed for %s, trying ArtifactHub fallback", stack.Language)
+					pkg, ahErr = ahClient.SearchPackage(cmd.Context(), stack.Language)
+				}
 				if ahErr != nil {
 					logger.Debugf("ArtifactHub fallback also failed for %s: %v", stack.Language, ahErr)
-					results = append(results, ClusterReleaseInfo{
-						Release:   stack.ReleaseName,
-						Namespace: orig.Namespace,
-						Chart:     orig.Chart,
-						Product:   stack.Language,
-						Version:   stack.Version,
-						Eol:       "unknown",
-						Risk:      "UNKNOWN",
-					})
-					continue
+					finding.EOL = "unknown"
+					finding.Risk = riskPolicy.UnknownLevel
+				} else {
+					finding.Risk, finding.EOL = artifacthub.RiskFromStalenessRules(*evaluator.Rules(scope).Staleness, riskPolicy.UnknownLevel, orig.AppVersion, pkg.AppVersion, pkg.Deprecated)
 				}
-				riskLevel, eolLabel := artifacthub.RiskFromStaleness(orig.AppVersion, pkg.AppVersion, pkg.Deprecated)
-				results = append(results, ClusterReleaseInfo{
-					Release:   stack.ReleaseName,
-					Namespace: orig.Namespace,
-					Chart:     orig.Chart,
-					Product:   stack.Language,
-					Version:   stack.Version,
-					Eol:       eolLabel,
-					Risk:      riskLevel,
-				})
-				continue
+			} else {
+				finding = rateCycle(finding, *cycle, evaluator, scope)
 			}
+			applyWaiver(&finding, waivers, scope, now)
 
-			if err := json.Unmarshal(productData, &resultMap); err != nil {
-				logger.Errorf("failed to parse JSON for %s: %v", stack.Language, err)
+			if !filter.keep(&finding) {
 				continue
 			}
+			gate.check(finding)
+			findings = append(findings, finding)
+		}
 
-			riskInfo := helpers.CalculateRisk(resultMap["eol"])
+		filter.finish(logger)
 
-			results = append(results, ClusterReleaseInfo{
-				Release:      stack.ReleaseName,
-				Namespace:    orig.Namespace,
-				Chart:        orig.Chart,
-				Product:      stack.Language,
-				Version:      stack.Version,
-				Eol:          helpers.GetStringValue(resultMap["eol"]),
-				Risk:         string(riskInfo.Level),
-				DaysUntilEOL: riskInfo.DaysUntilEOL,
-			})
+		rep := newReport(report.SourceCluster, findings)
+		rep.FailLevel = gate.junitLevel
+		addAIReports(cmd, rep, logger)
+		out, err := openOutput(output, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
 		}
-
-		if output == "json" {
-			jsonOutput, err := json.MarshalIndent(results, "", "  ")
-			if err != nil {
-				logger.Fatalf("failed to marshal results to JSON: %v", err)
-			}
-			fmt.Println(string(jsonOutput))
-		} else if output == "table" {
-			table := tablewriter.NewWriter(os.Stdout)
-			table.SetHeader([]string{"Release", "Namespace", "Product", "Version", "EOL", "Risk"})
-			table.SetAutoWrapText(false)
-
-			for _, r := range results {
-				renderRichRow(table, []string{r.Release, r.Namespace, r.Product, r.Version, r.Eol, r.Risk})
-			}
-			table.Render()
-		} else {
-			logger.Fatal("Invalid output type. Use 'table' or 'json'.")
+		defer out.Close()
+		if err := report.Render(out, output, rep); err != nil {
+			logger.Fatalf("%v", err)
 		}
 
-		riskReport, _ := cmd.Flags().GetBool("risk-report")
-		suggestVersion, _ := cmd.Flags().GetBool("suggest-version")
-
-		if riskReport || suggestVersion {
-			var riskItems []ai.RiskItem
-			var upgradeItems []ai.UpgradeItem
-
-			for _, item := range results {
-				riskItems = append(riskItems, ai.RiskItem{
-					Product:      item.Product,
-					Version:      item.Version,
-					EOL:          item.Eol,
-					RiskLevel:    item.Risk,
-					DaysUntilEOL: item.DaysUntilEOL,
-				})
-				upgradeItems = append(upgradeItems, ai.UpgradeItem{
-					Language:  item.Product,
-					Version:   item.Version,
-					EOL:       item.Eol,
-					RiskLevel: item.Risk,
-				})
-			}
-
-			if riskReport {
-				printRiskNarrative(riskItems, logger)
-			}
-			if suggestVersion {
-				printUpgradeSuggestions(upgradeItems, logger)
-			}
-		}
+		printAIReports(rep, output)
+		reportWaivers(waivers, now, logger)
+		gate.finish(logger)
 	},
 }
 
 func init() {
 	// registered in scan.go
+	addFailOnFlags(clusterCmd)
+	addBaselineFlags(clusterCmd)
 }
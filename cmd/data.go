@@ -0,0 +1,137 @@
+/*
+Copyright © 2024 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/asafdavid23/eolctl/internal/logging"
+	"github.com/asafdavid23/eolctl/pkg/bundle"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// dataCmd represents the data command
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Export and import offline snapshots of the endoflife.date catalog.",
+	Long: `The 'data' command manages offline snapshot bundles for air-gapped environments.
+Export a bundle on a machine with network access, copy it to the isolated host, import it there and run any command with --offline.`,
+	Run: func(cmd *cobra.Command, args []string) {
+	},
+}
+
+var dataExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Download the full product catalog into a versioned, checksummed bundle.",
+	Long: `The 'export' command downloads the list of available products and every product's release cycles
+into a single gzip-compressed archive with a manifest of SHA-256 checksums.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		file, _ := cmd.Flags().GetString("file")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		logger := logging.NewLogger(logLevel)
+
+		// A bundle must reflect the API at export time, not whatever the
+		// local cache is still willing to serve.
+		viper.Set("cache.refresh", true)
+
+		client, err := newEOLClient(logger)
+		if err != nil {
+			logger.Fatalf("Failed to configure endoflife.date client: %v", err)
+		}
+
+		logger.Infof("Downloading product catalog from %s", client.BaseURL())
+		b, err := bundle.Build(cmd.Context(), client, client.BaseURL(), concurrency, func(done, total int) {
+			logger.Debugf("Fetched %d/%d products", done, total)
+		})
+		if err != nil {
+			logger.Fatalf("Failed to build bundle: %v", err)
+		}
+
+		f, err := os.Create(file)
+		if err != nil {
+			logger.Fatalf("Failed to create bundle file: %v", err)
+		}
+
+		hash := sha256.New()
+		if err := b.Write(io.MultiWriter(f, hash)); err != nil {
+			f.Close()
+			logger.Fatalf("Failed to write bundle: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			logger.Fatalf("Failed to write bundle: %v", err)
+		}
+
+		fmt.Printf("Exported %d products to %s\n", b.Manifest.Products, file)
+		fmt.Printf("sha256: %s\n", hex.EncodeToString(hash.Sum(nil)))
+	},
+}
+
+var dataImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Verify a bundle and install it as the data source for --offline.",
+	Long: `The 'import' command verifies a bundle created by 'eolctl data export' and copies it to the
+location used by --offline (offline.bundle in the config, by default ~/.eolctl/bundle.tar.gz).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel, _ := cmd.Flags().GetString("log-level")
+		logger := logging.NewLogger(logLevel)
+
+		src := args[0]
+
+		b, err := bundle.Open(src)
+		if err != nil {
+			logger.Fatalf("Failed to verify bundle %s: %v", src, err)
+		}
+
+		dest, err := bundlePath()
+		if err != nil {
+			logger.Fatalf("Failed to resolve bundle location: %v", err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			logger.Fatalf("Failed to create bundle directory: %v", err)
+		}
+
+		data, err := os.ReadFile(src)
+		if err != nil {
+			logger.Fatalf("Failed to read bundle: %v", err)
+		}
+
+		// Write next to the destination and rename so a failed copy never
+		// leaves a truncated bundle behind.
+		tmp := dest + ".tmp"
+		if err := os.WriteFile(tmp, data, 0644); err != nil {
+			logger.Fatalf("Failed to write bundle: %v", err)
+		}
+		if err := os.Rename(tmp, dest); err != nil {
+			os.Remove(tmp)
+			logger.Fatalf("Failed to install bundle: %v", err)
+		}
+
+		fmt.Printf("Imported %d products created %s (%s old) to %s\n",
+			b.Manifest.Products, b.Manifest.CreatedAt.Format("2006-01-02 15:04 MST"), formatAge(b.Age()), dest)
+
+		if maxAge := offlineMaxAge(); b.Age() > maxAge {
+			logger.Warnf("Bundle is older than %s; export a fresh one to get current EOL data", formatAge(maxAge))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dataCmd)
+	dataCmd.AddCommand(dataExportCmd)
+	dataCmd.AddCommand(dataImportCmd)
+
+	dataExportCmd.Flags().StringP("file", "f", "eolctl-bundle.tar.gz", "Path of the bundle to write")
+	dataExportCmd.Flags().Int("concurrency", 8, "Number of products to download in parallel")
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	ai "github.com/asafdavid23/eolctl/pkg/ai"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+	"github.com/asafdavid23/eolctl/pkg/helpers"
+	"github.com/asafdavid23/eolctl/pkg/policy"
+	"github.com/asafdavid23/eolctl/pkg/report"
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+// newReport wraps findings from source in a report rated by the loaded
+// risk policy.
+func newReport(source string, findings []report.Finding) *report.Report {
+	return &report.Report{
+		Source:      source,
+		Version:     Version,
+		Levels:      riskPolicy.Levels,
+		GeneratedAt: time.Now().UTC(),
+		Findings:    findings,
+		NoHeaders:   noHeaders(),
+		Reminders:   calendarReminders(),
+	}
+}
+
+// newListing returns a report of a command that lists something other than
+// findings: data for the structured formats and rows for the others.
+func newListing(heading string, data interface{}, rows report.Rows) *report.Report {
+	return &report.Report{
+		Heading:     heading,
+		Version:     Version,
+		Levels:      riskPolicy.Levels,
+		GeneratedAt: time.Now().UTC(),
+		NoHeaders:   noHeaders(),
+		Data:        data,
+		Rows:        &rows,
+	}
+}
+
+// rateCycle fills the lifecycle fields of f from cycle c, rated by
+// evaluator in scope. A full installed version in f.Version is also rated
+// against the cycle's latest release.
+func rateCycle(f report.Finding, c endoflife.Cycle, evaluator helpers.Evaluator, scope policy.Scope) report.Finding {
+	riskInfo := evaluator.Evaluate(c, scope)
+
+	f.Cycle = c.Cycle
+	f.Latest = c.Latest
+	f.ReleaseDate = c.ReleaseDate
+	f.LatestReleaseDate = c.LatestReleaseDate
+	f.LTS = c.LTS.String()
+	f.EOL = c.EOL.String()
+	f.Support = c.Support.String()
+	if c.ExtendedSupport.IsSet() {
+		f.ExtendedSupport = c.ExtendedSupport.String()
+	}
+	f.SupportPhase = string(riskInfo.Phase)
+	f.Risk = string(riskInfo.Level)
+	if riskInfo.Dated {
+		days := riskInfo.DaysUntilEOL
+		f.DaysUntilEOL = &days
+		if _, err := time.Parse(time.DateOnly, riskInfo.EOLDate); err == nil {
+			f.EOLDate = riskInfo.EOLDate
+		}
+	}
+
+	if f.Version != "" {
+		patchInfo := evaluator.PatchRisk(f.Version, c, scope)
+		f.ReleasesBehind = patchInfo.ReleasesBehind
+		if patchInfo.ReleasesBehind != nil || f.Source != report.SourceProduct {
+			f.PatchRisk = string(patchInfo.Level)
+		}
+	}
+	return f
+}
+
+// productFindings rates cycles of product name. A version that is not the
+// cycle name itself is rated against the cycle's latest patch release.
+func productFindings(name, version string, cycles []endoflife.Cycle, evaluator helpers.Evaluator, waivers *waiver.Set, now time.Time) []report.Finding {
+	scope := policy.Scope{Product: name}
+	var findings []report.Finding
+	for _, c := range cycles {
+		finding := report.Finding{Source: report.SourceProduct, Product: name}
+		if version != c.Cycle {
+			finding.Version = version
+		}
+		finding = rateCycle(finding, c, evaluator, scope)
+		applyWaiver(&finding, waivers, scope, now)
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// applyWaiver marks f as ACCEPTED if a waiver in set covers it at now.
+func applyWaiver(f *report.Finding, set *waiver.Set, scope policy.Scope, now time.Time) {
+	version := f.Version
+	if version == "" {
+		version = f.Cycle
+	}
+	if w, ok := set.Match(scope, version, f.Cycle, now); ok {
+		f.AcceptedRisk, f.Risk, f.Waiver = f.Risk, waiver.Accepted, w
+	}
+}
+
+// addAIReports generates the AI risk narrative and upgrade suggestions for
+// the findings of r when --risk-report or --suggest-version is set.
+func addAIReports(cmd *cobra.Command, r *report.Report, logger *log.Logger) {
+	riskReport, _ := cmd.Flags().GetBool("risk-report")
+	suggestVersion, _ := cmd.Flags().GetBool("suggest-version")
+	if !riskReport && !suggestVersion {
+		return
+	}
+
+	var riskItems []ai.RiskItem
+	var upgradeItems []ai.UpgradeItem
+	for _, f := range r.Findings {
+		days := -1
+		if f.DaysUntilEOL != nil {
+			days = *f.DaysUntilEOL
+		}
+		riskItems = append(riskItems, ai.RiskItem{
+			Product:      f.Product,
+			Version:      f.DisplayVersion(),
+			EOL:          f.EOL,
+			RiskLevel:    f.Risk,
+			DaysUntilEOL: days,
+		})
+		upgradeItems = append(upgradeItems, ai.UpgradeItem{
+			Language:  f.Product,
+			Version:   f.DisplayVersion(),
+			EOL:       f.EOL,
+			RiskLevel: f.Risk,
+		})
+	}
+
+	if riskReport {
+		r.Narrative = riskNarrative(riskItems, logger)
+	}
+	if suggestVersion {
+		r.Suggestions = upgradeSuggestions(upgradeItems, logger)
+	}
+}
+
+// printAIReports prints the AI reports of r after the report itself,
+// unless the output format embeds them.
+func printAIReports(r *report.Report, format string) {
+	if report.EmbedsAIReports(format) {
+		return
+	}
+	if r.Narrative != "" {
+		fmt.Println("\n--- AI Risk Summary ---")
+		fmt.Println(r.Narrative)
+	}
+	if r.Suggestions != "" {
+		fmt.Println("\n--- AI Upgrade Suggestions ---")
+		fmt.Println(r.Suggestions)
+	}
+}
+
+// findingID identifies a finding across scans for baselines: the release
+// for clusters, the manifest and product otherwise.
+func findingID(f report.Finding) string {
+	if f.Source == report.SourceCluster {
+		return f.Location
+	}
+	return strings.TrimPrefix(f.Location+":"+f.Product, ":")
+}
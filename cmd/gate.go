@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/asafdavid23/eolctl/pkg/report"
+)
+
+// exitFindings is the exit code when findings meet --fail-on or
+// --fail-on-days. Operational errors exit with 1.
+const exitFindings = 2
+
+// addFailOnFlags registers the CI gate flags on a command that reports
+// findings.
+func addFailOnFlags(cmd *cobra.Command) {
+	cmd.Flags().String("fail-on", "", fmt.Sprintf("Exit with code %d when a component's risk is at this level or more severe", exitFindings))
+	cmd.Flags().Int("fail-on-days", -1, fmt.Sprintf("Exit with code %d when a component reaches EOL within this many days", exitFindings))
+}
+
+// failGate collects the findings that meet the --fail-on thresholds.
+type failGate struct {
+	level    string
+	rank     int
+	days     int
+	checked  int
+	accepted int
+	failures []string
+	// junitLevel is the level at which JUnit testcases fail:
+	// junit.fail_level, or the --fail-on level.
+	junitLevel string
+}
+
+// newFailGate reads the gate thresholds from the command flags, falling
+// back to fail_on.level and fail_on.days in the config. It must run after
+// newEvaluator so levels are checked against the loaded policy.
+func newFailGate(cmd *cobra.Command) (*failGate, error) {
+	level := viper.GetString("fail_on.level")
+	if f := cmd.Flags().Lookup("fail-on"); f != nil && f.Changed {
+		level = f.Value.String()
+	}
+	days := -1
+	if viper.IsSet("fail_on.days") {
+		days = viper.GetInt("fail_on.days")
+	}
+	if f := cmd.Flags().Lookup("fail-on-days"); f != nil && f.Changed {
+		days, _ = cmd.Flags().GetInt("fail-on-days")
+	}
+
+	g := &failGate{rank: -1, days: days}
+	if level = strings.TrimSpace(level); level != "" {
+		g.rank = policyRank(level)
+		if g.rank < 0 {
+			return nil, fmt.Errorf("invalid --fail-on level %q, expected one of %s", level, strings.Join(riskPolicy.Levels, ", "))
+		}
+		g.level = riskPolicy.Levels[g.rank]
+	}
+
+	g.junitLevel = g.level
+	if level := strings.TrimSpace(viper.GetString("junit.fail_level")); level != "" {
+		rank := policyRank(level)
+		if rank < 0 {
+			return nil, fmt.Errorf("invalid junit.fail_level %q, expected one of %s", level, strings.Join(riskPolicy.Levels, ", "))
+		}
+		g.junitLevel = riskPolicy.Levels[rank]
+	}
+	return g, nil
+}
+
+// policyRank returns the rank of level in the risk policy, compared
+// case-insensitively, or -1.
+func policyRank(level string) int {
+	for i, l := range riskPolicy.Levels {
+		if strings.EqualFold(l, level) {
+			return i
+		}
+	}
+	return -1
+}
+
+// check records f as a failure if its risk meets the gate. Waived findings
+// never fail it.
+func (g *failGate) check(f report.Finding) {
+	g.checked++
+	if f.Waiver != nil {
+		g.accepted++
+		return
+	}
+
+	var reasons []string
+	if g.level != "" {
+		if r := riskPolicy.Rank(f.Risk); r >= 0 && r <= g.rank {
+			reasons = append(reasons, f.Risk)
+		}
+	}
+	if d := f.DaysUntilEOL; g.days >= 0 && d != nil && *d <= g.days {
+		switch {
+		case *d < 0:
+			reasons = append(reasons, fmt.Sprintf("EOL %d days ago", -*d))
+		case *d == 0:
+			reasons = append(reasons, "EOL")
+		default:
+			reasons = append(reasons, fmt.Sprintf("EOL in %d days", *d))
+		}
+	}
+	if len(reasons) > 0 {
+		g.failures = append(g.failures, fmt.Sprintf("%s (%s)", f.Name(), strings.Join(reasons, ", ")))
+	}
+}
+
+func (g *failGate) enabled() bool {
+	return g.level != "" || g.days >= 0
+}
+
+func (g *failGate) describe() string {
+	var parts []string
+	if g.level != "" {
+		parts = append(parts, "--fail-on "+g.level)
+	}
+	if g.days >= 0 {
+		parts = append(parts, fmt.Sprintf("--fail-on-days %d", g.days))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// summary returns the gate result line and whether the gate passed. It is
+// empty when no threshold is set.
+func (g *failGate) summary() (string, bool) {
+	if !g.enabled() {
+		return "", true
+	}
+	var accepted string
+	if g.accepted > 0 {
+		accepted = fmt.Sprintf(" (%d accepted by waivers)", g.accepted)
+	}
+	if len(g.failures) == 0 {
+		return fmt.Sprintf("PASS: no component meets %s%s", g.describe(), accepted), true
+	}
+	return fmt.Sprintf("FAIL: %d of %d component(s) meet %s%s: %s",
+		len(g.failures), g.checked, g.describe(), accepted, strings.Join(g.failures, "; ")), false
+}
+
+// finish prints the gate summary and, when any finding met the thresholds,
+// sets the process exit code to exitFindings. The command then returns
+// normally so its output and the cache are flushed before Execute exits.
+func (g *failGate) finish(logger *log.Logger) {
+	line, passed := g.summary()
+	switch {
+	case line == "":
+	case passed:
+		logger.Info(line)
+	default:
+		fmt.Fprintln(os.Stderr, line)
+		exitCode = exitFindings
+	}
+}
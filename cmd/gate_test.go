@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/asafdavid23/eolctl/pkg/report"
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+func gateFor(t *testing.T, args ...string) *failGate {
+	t.Helper()
+	t.Cleanup(viper.Reset)
+	cmd := &cobra.Command{Use: "test"}
+	addFailOnFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	g, err := newFailGate(cmd)
+	if err != nil {
+		t.Fatalf("newFailGate: %v", err)
+	}
+	return g
+}
+
+func days(d int) *int { return &d }
+
+func TestFailGate(t *testing.T) {
+	findings := []report.Finding{
+		{Product: "python", Version: "3.8.10", Risk: "CRITICAL", DaysUntilEOL: days(-400)},
+		{Product: "nodejs", Version: "18.20.4", Risk: "HIGH", DaysUntilEOL: days(20)},
+		{Product: "go", Version: "1.22.5", Risk: "MEDIUM", DaysUntilEOL: days(0)},
+		{Product: "ruby", Version: "3.3.1", Risk: "LOW", DaysUntilEOL: days(500)},
+		{Product: "nginx", Version: "1.25.3", Risk: "UNKNOWN"},
+		{Product: "php", Version: "7.4.33", Risk: "CRITICAL", DaysUntilEOL: days(-700), Waiver: &waiver.Waiver{Reason: "retiring"}},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantSummary string
+		wantPassed  bool
+	}{
+		{
+			name:       "disabled",
+			wantPassed: true,
+		},
+		{
+			name:        "level threshold",
+			args:        []string{"--fail-on", "high"},
+			wantSummary: "FAIL: 2 of 6 component(s) meet --fail-on HIGH (1 accepted by waivers): python 3.8.10 (CRITICAL); nodejs 18.20.4 (HIGH)",
+		},
+		{
+			name:        "days threshold",
+			args:        []string{"--fail-on-days", "30"},
+			wantSummary: "FAIL: 3 of 6 component(s) meet --fail-on-days 30 (1 accepted by waivers): python 3.8.10 (EOL 400 days ago); nodejs 18.20.4 (EOL in 20 days); go 1.22.5 (EOL)",
+		},
+		{
+			name:        "both thresholds",
+			args:        []string{"--fail-on", "CRITICAL", "--fail-on-days", "0"},
+			wantSummary: "FAIL: 2 of 6 component(s) meet --fail-on CRITICAL / --fail-on-days 0 (1 accepted by waivers): python 3.8.10 (CRITICAL, EOL 400 days ago); go 1.22.5 (EOL)",
+		},
+		{
+			name:        "negative days disable the days threshold",
+			args:        []string{"--fail-on-days", "-1", "--fail-on", "CRITICAL"},
+			wantSummary: "FAIL: 1 of 6 component(s) meet --fail-on CRITICAL (1 accepted by waivers): python 3.8.10 (CRITICAL)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gateFor(t, tt.args...)
+			for _, f := range findings {
+				g.check(f)
+			}
+			summary, passed := g.summary()
+			if summary != tt.wantSummary || passed != tt.wantPassed {
+				t.Errorf("summary() =\n  %q, %v\nwant\n  %q, %v", summary, passed, tt.wantSummary, tt.wantPassed)
+			}
+		})
+	}
+}
+
+func TestFailGatePass(t *testing.T) {
+	g := gateFor(t, "--fail-on", "CRITICAL")
+	g.check(report.Finding{Product: "go", Version: "1.23.4", Risk: "LOW", DaysUntilEOL: days(200)})
+	g.check(report.Finding{Product: "php", Version: "7.4.33", Risk: "CRITICAL", Waiver: &waiver.Waiver{Reason: "retiring"}})
+
+	summary, passed := g.summary()
+	if want := "PASS: no component meets --fail-on CRITICAL (1 accepted by waivers)"; summary != want || !passed {
+		t.Errorf("summary() = %q, %v; want %q, true", summary, passed, want)
+	}
+}
+
+func TestFailGateFinishSetsExitCode(t *testing.T) {
+	t.Cleanup(func() { exitCode = 0 })
+	logger, _ := test.NewNullLogger()
+
+	g := gateFor(t, "--fail-on", "HIGH")
+	g.check(report.Finding{Product: "go", Version: "1.23.4", Risk: "LOW"})
+	g.finish(logger)
+	if exitCode != 0 {
+		t.Errorf("exit code after a passing gate = %d, want 0", exitCode)
+	}
+
+	g.check(report.Finding{Product: "python", Version: "3.8.10", Risk: "CRITICAL"})
+	g.finish(logger)
+	if exitCode != exitFindings {
+		t.Errorf("exit code after a failing gate = %d, want %d", exitCode, exitFindings)
+	}
+}
+
+func TestFailGateConfig(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set("fail_on.level", "medium")
+	viper.Set("fail_on.days", 90)
+	viper.Set("junit.fail_level", "low")
+
+	cmd := &cobra.Command{Use: "test"}
+	addFailOnFlags(cmd)
+	g, err := newFailGate(cmd)
+	if err != nil {
+		t.Fatalf("newFailGate: %v", err)
+	}
+	if g.level != "MEDIUM" || g.days != 90 || g.junitLevel != "LOW" {
+		t.Errorf("gate = level %q, days %d, junit level %q; want MEDIUM, 90, LOW", g.level, g.days, g.junitLevel)
+	}
+
+	cmd.ParseFlags([]string{"--fail-on", "bogus"})
+	if _, err := newFailGate(cmd); err == nil {
+		t.Error("newFailGate accepted an unknown --fail-on level")
+	}
+}
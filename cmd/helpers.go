@@ -1,62 +1,403 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 
+	localCache "github.com/asafdavid23/eolctl/internal/cache"
 	ai "github.com/asafdavid23/eolctl/pkg/ai"
+	"github.com/asafdavid23/eolctl/pkg/artifacthub"
+	"github.com/asafdavid23/eolctl/pkg/bundle"
+	"github.com/asafdavid23/eolctl/pkg/catalog"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+	"github.com/asafdavid23/eolctl/pkg/helpers"
+	"github.com/asafdavid23/eolctl/pkg/httpclient"
+	"github.com/asafdavid23/eolctl/pkg/policy"
+	"github.com/asafdavid23/eolctl/pkg/resolve"
 )
 
-func printRiskNarrative(items []ai.RiskItem, logger *log.Logger) {
+// httpConfig reads the connection settings for an upstream API from the
+// given config section. ca_file, proxy, timeouts and retries fall back to the
+// shared "http" section so organisation-wide settings only have to be set once.
+func httpConfig(section string) httpclient.Config {
+	cfg := httpclient.Config{
+		BaseURL: viper.GetString(section + ".base_url"),
+		Headers: viper.GetStringMapString(section + ".headers"),
+		Token:   viper.GetString(section + ".token"),
+		CAFile:  viper.GetString(section + ".ca_file"),
+		Proxy:   viper.GetString(section + ".proxy"),
+	}
+	if cfg.CAFile == "" {
+		cfg.CAFile = viper.GetString("http.ca_file")
+	}
+	if cfg.Proxy == "" {
+		cfg.Proxy = viper.GetString("http.proxy")
+	}
+
+	cfg.Timeout = viper.GetDuration(httpKey(section, "timeout"))
+	cfg.Retries = viper.GetInt(httpKey(section, "retries"))
+	cfg.RetryWait = viper.GetDuration(httpKey(section, "retry_wait"))
+	cfg.RetryMaxWait = viper.GetDuration(httpKey(section, "retry_max_wait"))
+	return cfg
+}
+
+// httpKey returns section.name when it is set and http.name otherwise.
+func httpKey(section, name string) string {
+	if viper.IsSet(section + "." + name) {
+		return section + "." + name
+	}
+	return "http." + name
+}
+
+func newEOLClient(logger *log.Logger) (*endoflife.Client, error) {
+	cfg := httpConfig("endoflife")
+	cfg.Logf = logger.Debugf
+	httpClient, err := httpclient.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	withCache(httpClient, cfg, eolCacheTTL, logger)
+
+	opts := []endoflife.Option{endoflife.WithHTTPClient(httpClient)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, endoflife.WithBaseURL(cfg.BaseURL))
+	}
+	return endoflife.NewClient(opts...), nil
+}
+
+var (
+	cacheStore     localCache.Store
+	cacheStoreErr  error
+	cacheStoreOnce sync.Once
+)
+
+// openCacheStore opens the configured cache backend, once per run.
+func openCacheStore(logger *log.Logger) (localCache.Store, error) {
+	cacheStoreOnce.Do(func() {
+		cacheStore, cacheStoreErr = localCache.Open(localCache.Config{
+			Backend: viper.GetString("cache.backend"),
+			Path:    viper.GetString("cache.path"),
+			Redis: localCache.RedisConfig{
+				Addr:     viper.GetString("cache.redis.addr"),
+				Username: viper.GetString("cache.redis.username"),
+				Password: viper.GetString("cache.redis.password"),
+				DB:       viper.GetInt("cache.redis.db"),
+				Prefix:   viper.GetString("cache.redis.prefix"),
+				TLS:      viper.GetBool("cache.redis.tls"),
+				Timeout:  viper.GetDuration("cache.redis.timeout"),
+			},
+			Logger: logger,
+		})
+	})
+	return cacheStore, cacheStoreErr
+}
+
+// closeCacheStore waits for background revalidations and persists the cache.
+func closeCacheStore() error {
+	localCache.Wait()
+	if cacheStore == nil {
+		return nil
+	}
+	return cacheStore.Close()
+}
+
+// saveCacheStore persists the cache without closing it, for long-running
+// commands. Backends that write through have nothing to save.
+func saveCacheStore() error {
+	saver, ok := cacheStore.(interface{ Save() error })
+	if !ok {
+		return nil
+	}
+	return saver.Save()
+}
+
+// withCache routes httpClient through the local response cache unless
+// caching is disabled for this run. Background revalidations get as long as
+// a request made with cfg may take, retries included.
+func withCache(httpClient *http.Client, cfg httpclient.Config, ttl localCache.TTLFunc, logger *log.Logger) {
+	mode := localCache.ModeDefault
+	switch {
+	case viper.GetBool("cache.disabled"):
+		return
+	case viper.GetBool("cache.refresh"):
+		mode = localCache.ModeRefresh
+	}
+
+	store, err := openCacheStore(logger)
+	if err != nil {
+		logger.Warnf("Failed to open cache, continuing without cache: %v", err)
+		return
+	}
+
+	httpClient.Transport = &localCache.Transport{
+		Base:              httpClient.Transport,
+		Store:             store,
+		Mode:              mode,
+		TTL:               ttl,
+		StaleFor:          viper.GetDuration("cache.stale_for"),
+		RevalidateTimeout: cfg.MaxDuration(),
+		Logf:              logger.Debugf,
+	}
+}
+
+// eolCacheTTL keeps the product list longer than per-product cycle data,
+// which changes whenever a product ships a release.
+func eolCacheTTL(req *http.Request) time.Duration {
+	if strings.HasSuffix(req.URL.Path, "/all.json") {
+		return viper.GetDuration("cache.ttl.products")
+	}
+	return viper.GetDuration("cache.ttl.cycles")
+}
+
+func artifactHubCacheTTL(req *http.Request) time.Duration {
+	return viper.GetDuration("cache.ttl.artifacthub")
+}
+
+// newEOLProvider returns the source of EOL data for this run: the live API
+// or the offline bundle, overlaid with the local product catalog if one is
+// configured.
+func newEOLProvider(logger *log.Logger) (endoflife.Provider, error) {
+	files := viper.GetStringSlice("catalog.files")
+	if len(files) == 0 {
+		return newPublicProvider(logger)
+	}
+
+	c, err := catalog.Load(files...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Loaded %d product(s) from the local catalog", len(c.Products()))
+
+	if viper.GetBool("catalog.only") {
+		return &catalog.Overlay{Catalog: c}, nil
+	}
+
+	base, err := newPublicProvider(logger)
+	if err != nil {
+		return nil, err
+	}
+	return &catalog.Overlay{Base: base, Catalog: c}, nil
+}
+
+// newPublicProvider returns the endoflife.date client, or the offline bundle
+// in offline mode.
+func newPublicProvider(logger *log.Logger) (endoflife.Provider, error) {
+	if !viper.GetBool("offline.enabled") {
+		return newEOLClient(logger)
+	}
+
+	path, err := bundlePath()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := bundle.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open offline bundle (run 'eolctl data import' first): %w", err)
+	}
+
+	logger.Infof("Offline mode: using bundle created %s (%s old)", b.Manifest.CreatedAt.Format("2006-01-02"), formatAge(b.Age()))
+	if maxAge := offlineMaxAge(); b.Age() > maxAge {
+		logger.Warnf("Offline bundle is older than %s; EOL data may be stale", formatAge(maxAge))
+	}
+
+	return b, nil
+}
+
+// bundlePath returns where the offline bundle is imported to and read from.
+func bundlePath() (string, error) {
+	if path := viper.GetString("offline.bundle"); path != "" {
+		return path, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".eolctl", "bundle.tar.gz"), nil
+}
+
+func offlineMaxAge() time.Duration {
+	if maxAge := viper.GetDuration("offline.max_age"); maxAge > 0 {
+		return maxAge
+	}
+	return 30 * 24 * time.Hour
+}
+
+// formatAge renders a duration in days, hours or minutes.
+func formatAge(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// newResolver builds a product name resolver over the products the provider
+// knows, with the user's "aliases" config merged over the built-in table.
+func newResolver(ctx context.Context, client endoflife.Provider) (*resolve.Resolver, error) {
+	products, err := client.GetAvailableProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resolve.New(products, viper.GetStringMapString("aliases")), nil
+}
+
+// resolveProductName resolves a user-supplied product name. Unknown names
+// are fatal with "did you mean" suggestions, unless the terminal is
+// interactive, in which case the user can pick one of the suggestions.
+func resolveProductName(resolver *resolve.Resolver, name string, logger *log.Logger) string {
+	product, err := resolver.Resolve(name)
+	if err == nil {
+		if product != name {
+			logger.Infof("Using product %q for %q", product, name)
+		}
+		return product
+	}
+
+	var notFound *resolve.NotFoundError
+	if !errors.As(err, &notFound) || len(notFound.Suggestions) == 0 || !isInteractive() {
+		logger.Fatalf("%v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown product %q. Did you mean:\n", name)
+	for i, s := range notFound.Suggestions {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, s)
+	}
+	fmt.Fprintf(os.Stderr, "Select [1-%d, Enter to cancel]: ", len(notFound.Suggestions))
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	choice, convErr := strconv.Atoi(strings.TrimSpace(line))
+	if convErr != nil || choice < 1 || choice > len(notFound.Suggestions) {
+		logger.Fatalf("%v", err)
+	}
+	return notFound.Suggestions[choice-1]
+}
+
+// isInteractive reports whether both stdin and stdout are terminals.
+func isInteractive() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		fi, err := f.Stat()
+		if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func newArtifactHubClient(logger *log.Logger) (*artifacthub.Client, error) {
+	cfg := httpConfig("artifacthub")
+	cfg.Logf = logger.Debugf
+	httpClient, err := httpclient.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	withCache(httpClient, cfg, artifactHubCacheTTL, logger)
+
+	opts := []artifacthub.Option{artifacthub.WithHTTPClient(httpClient)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, artifacthub.WithBaseURL(cfg.BaseURL))
+	}
+	return artifacthub.NewClient(opts...), nil
+}
+
+// riskNarrative asks Claude for a risk narrative of items, returning ""
+// after logging the reason when none can be generated.
+func riskNarrative(items []ai.RiskItem, logger *log.Logger) string {
 	if len(items) == 0 {
 		logger.Warn("no risk data to summarize — no components were successfully scanned")
-		return
+		return ""
 	}
 	narrative, err := ai.GenerateRiskNarrative(items)
 	if err != nil {
 		logger.Errorf("failed to generate risk narrative: %v", err)
-		return
+		return ""
 	}
-	fmt.Println("\n--- AI Risk Summary ---")
-	fmt.Println(narrative)
+	return narrative
 }
 
-func printUpgradeSuggestions(items []ai.UpgradeItem, logger *log.Logger) {
+// upgradeSuggestions asks Claude for upgrade suggestions for items,
+// returning "" after logging the reason when none can be generated.
+func upgradeSuggestions(items []ai.UpgradeItem, logger *log.Logger) string {
 	if len(items) == 0 {
 		logger.Warn("no upgrade data available — no components were successfully scanned")
-		return
+		return ""
 	}
 	suggestions, err := ai.SuggestUpgradePath(items)
 	if err != nil {
 		logger.Errorf("failed to generate upgrade suggestions: %v", err)
-		return
+		return ""
 	}
-	fmt.Println("\n--- AI Upgrade Suggestions ---")
-	fmt.Println(suggestions)
+	return suggestions
 }
 
-func riskLevelColor(level string) tablewriter.Colors {
-	switch level {
-	case "CRITICAL":
-		return tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
-	case "HIGH":
-		return tablewriter.Colors{tablewriter.FgRedColor}
-	case "MEDIUM":
-		return tablewriter.Colors{tablewriter.FgYellowColor}
-	case "LOW":
-		return tablewriter.Colors{tablewriter.FgGreenColor}
-	default:
-		return tablewriter.Colors{}
+// riskPolicy is the policy loaded by newEvaluator.
+var riskPolicy = policy.Default()
+
+// newEvaluator returns the risk evaluator configured for this run. The
+// policy comes from the file named by risk.policy_file (--policy) or, if
+// unset, from the "risk" section of the config file.
+func newEvaluator(logger *log.Logger) (helpers.Evaluator, error) {
+	settings := viper.GetStringMap("risk")
+	countExtended := viper.GetBool("risk.count_extended_support")
+
+	if file := viper.GetString("risk.policy_file"); file != "" {
+		v := viper.New()
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			return helpers.Evaluator{}, fmt.Errorf("failed to read risk policy: %w", err)
+		}
+		settings = v.AllSettings()
+		countExtended = countExtended || v.GetBool("count_extended_support")
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return helpers.Evaluator{}, fmt.Errorf("invalid risk policy: %w", err)
+	}
+	p, err := policy.Parse(data)
+	if err != nil {
+		return helpers.Evaluator{}, fmt.Errorf("invalid risk policy: %w", err)
 	}
+	riskPolicy = p
+
+	evaluator := helpers.Evaluator{Policy: p, CountExtendedSupport: countExtended}
+	if asOf, ok, err := evaluationDate(); err != nil {
+		return helpers.Evaluator{}, err
+	} else if ok {
+		logger.Infof("Evaluating risk as of %s", asOf.Format(time.DateOnly))
+		evaluator.Now = func() time.Time { return asOf }
+	}
+	return evaluator, nil
 }
 
-func renderRichRow(table *tablewriter.Table, row []string) {
-	colors := make([]tablewriter.Colors, len(row))
-	for i := range colors {
-		colors[i] = tablewriter.Colors{}
+// evaluationDate returns the --as-of date (as_of), if set. Dates are taken
+// as midnight UTC; a full RFC 3339 timestamp is accepted too.
+func evaluationDate() (time.Time, bool, error) {
+	value := strings.TrimSpace(viper.GetString("as_of"))
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true, nil
 	}
-	colors[len(row)-1] = riskLevelColor(row[len(row)-1])
-	table.Rich(row, colors)
+	return time.Time{}, false, fmt.Errorf("invalid --as-of date %q, expected YYYY-MM-DD", value)
 }
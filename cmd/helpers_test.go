@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/viper"
+
+	"github.com/asafdavid23/eolctl/pkg/bundle"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+)
+
+// staticProvider serves a fixed set of products.
+type staticProvider map[string][]endoflife.Cycle
+
+func (p staticProvider) GetAvailableProducts(ctx context.Context) ([]string, error) {
+	var products []string
+	for product := range p {
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+func (p staticProvider) GetProduct(ctx context.Context, product string) ([]endoflife.Cycle, error) {
+	if cycles, ok := p[product]; ok {
+		return cycles, nil
+	}
+	return nil, endoflife.ErrNotFound
+}
+
+func (p staticProvider) GetCycle(ctx context.Context, product, cycle string) (*endoflife.Cycle, error) {
+	return nil, endoflife.ErrNotFound
+}
+
+// writeBundle writes an offline bundle created at createdAt and returns its
+// path.
+func writeBundle(t *testing.T, createdAt time.Time) string {
+	t.Helper()
+	b, err := bundle.Build(context.Background(), staticProvider{"go": {{Cycle: "1.23"}}}, "test", 1, nil)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	b.Manifest.CreatedAt = createdAt
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := b.Write(f); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return path
+}
+
+func TestNewPublicProviderOffline(t *testing.T) {
+	tests := []struct {
+		name     string
+		age      time.Duration
+		maxAge   string
+		wantWarn bool
+	}{
+		{"fresh bundle", time.Hour, "", false},
+		{"older than the default max age", 31 * 24 * time.Hour, "", true},
+		{"within a configured max age", 31 * 24 * time.Hour, "1000h", false},
+		{"older than a configured max age", 3 * 24 * time.Hour, "48h", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+			viper.Set("offline.enabled", true)
+			viper.Set("offline.bundle", writeBundle(t, time.Now().Add(-tt.age)))
+			if tt.maxAge != "" {
+				viper.Set("offline.max_age", tt.maxAge)
+			}
+
+			logger, hook := test.NewNullLogger()
+			p, err := newPublicProvider(logger)
+			if err != nil {
+				t.Fatalf("newPublicProvider: %v", err)
+			}
+			if _, ok := p.(*bundle.Bundle); !ok {
+				t.Errorf("provider = %T, want the offline bundle", p)
+			}
+
+			var warned bool
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == log.WarnLevel && strings.Contains(entry.Message, "EOL data may be stale") {
+					warned = true
+				}
+			}
+			if warned != tt.wantWarn {
+				t.Errorf("stale warning logged = %v, want %v", warned, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestNewPublicProviderMissingBundle(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set("offline.enabled", true)
+	viper.Set("offline.bundle", filepath.Join(t.TempDir(), "missing.tar.gz"))
+
+	logger, _ := test.NewNullLogger()
+	_, err := newPublicProvider(logger)
+	if err == nil || !strings.Contains(err.Error(), "run 'eolctl data import' first") {
+		t.Errorf("newPublicProvider() error = %v, want a hint to import a bundle", err)
+	}
+}
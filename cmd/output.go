@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/asafdavid23/eolctl/pkg/report"
+)
+
+// formatExtensions are the file extensions of the output formats, used
+// when --output-path names a directory.
+var formatExtensions = map[string]string{
+	report.Table:    "txt",
+	report.JSON:     "json",
+	report.YAML:     "yaml",
+	report.CSV:      "csv",
+	report.TSV:      "tsv",
+	report.ICS:      "ics",
+	report.SARIF:    "sarif",
+	report.JUnit:    "xml",
+	report.Markdown: "md",
+	report.HTML:     "html",
+}
+
+// openOutput returns where results in format are written: the file named
+// by output.path (--output-path), or stdout. A path naming an existing
+// directory gets an output.<ext> file inside it.
+func openOutput(format string, logger *log.Logger) (io.WriteCloser, error) {
+	path := viper.GetString("output.path")
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		ext, ok := formatExtensions[format]
+		if !ok {
+			ext = format
+		}
+		path = filepath.Join(path, "output."+ext)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("Writing %s output to %s", format, path)
+	return f, nil
+}
+
+// noHeaders reports whether --no-headers is set.
+func noHeaders() bool {
+	return viper.GetBool("output.no_headers")
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/viper"
+
+	"github.com/asafdavid23/eolctl/pkg/report"
+)
+
+func TestOpenOutput(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "results.json")
+
+	tests := []struct {
+		name     string
+		path     string
+		format   string
+		wantPath string
+		wantErr  bool
+	}{
+		{name: "stdout", format: report.JSON},
+		{name: "file", path: file, format: report.JSON, wantPath: file},
+		{name: "file keeps its name whatever the format", path: file, format: report.CSV, wantPath: file},
+		{name: "directory, table", path: dir, format: report.Table, wantPath: filepath.Join(dir, "output.txt")},
+		{name: "directory, json", path: dir, format: report.JSON, wantPath: filepath.Join(dir, "output.json")},
+		{name: "directory, junit", path: dir, format: report.JUnit, wantPath: filepath.Join(dir, "output.xml")},
+		{name: "directory, markdown", path: dir, format: report.Markdown, wantPath: filepath.Join(dir, "output.md")},
+		{name: "directory, unknown extension", path: dir, format: "custom", wantPath: filepath.Join(dir, "output.custom")},
+		{name: "missing parent directory", path: filepath.Join(dir, "missing", "out.json"), format: report.JSON, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+			viper.Set("output.path", tt.path)
+			logger, hook := test.NewNullLogger()
+
+			out, err := openOutput(tt.format, logger)
+			if tt.wantErr {
+				if err == nil {
+					out.Close()
+					t.Fatal("openOutput succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("openOutput: %v", err)
+			}
+			if tt.wantPath == "" {
+				if _, ok := out.(nopCloser); !ok {
+					t.Errorf("openOutput returned %T, want stdout", out)
+				}
+				if len(hook.AllEntries()) != 0 {
+					t.Errorf("logged %q writing to stdout", hook.LastEntry().Message)
+				}
+				return
+			}
+
+			if _, err := io.WriteString(out, "results\n"); err != nil {
+				t.Fatal(err)
+			}
+			if err := out.Close(); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(tt.wantPath)
+			if err != nil || string(data) != "results\n" {
+				t.Errorf("%s = %q, %v; want the output", tt.wantPath, data, err)
+			}
+			if e := hook.LastEntry(); e == nil || e.Message != "Writing "+tt.format+" output to "+tt.wantPath {
+				t.Errorf("log entry = %v, want the output path", e)
+			}
+		})
+	}
+}
+
+func TestFormatExtensions(t *testing.T) {
+	for _, format := range report.Formats() {
+		if formatExtensions[format] == "" {
+			t.Errorf("format %s has no file extension for --output-path directories", format)
+		}
+	}
+}
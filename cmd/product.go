@@ -4,17 +4,12 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
-
 	"github.com/asafdavid23/eolctl/internal/logging"
-	ai "github.com/asafdavid23/eolctl/pkg/ai"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
 	helpers "github.com/asafdavid23/eolctl/pkg/helpers"
+	"github.com/asafdavid23/eolctl/pkg/report"
 
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // productCmd represents the product command
@@ -25,168 +20,108 @@ var productCmd = &cobra.Command{
 By specifying the product name or ID, you can retrieve its EOL status, version information, and other relevant details.`,
 	Run: func(cmd *cobra.Command, args []string) {
 
-		initConfig()
-
 		var enableCustomRange bool
-		var outputData []byte
+		var cycles []endoflife.Cycle
+		var single *endoflife.Cycle
 
 		name, _ := cmd.Flags().GetString("name")
 		version, _ := cmd.Flags().GetString("version")
-		outputFolder := viper.GetString("output.path")
 		minVersion, _ := cmd.Flags().GetString("min")
 		maxVersion, _ := cmd.Flags().GetString("max")
 		output, _ := cmd.Flags().GetString("output")
 		logLevel, _ := cmd.Flags().GetString("log-level")
 
 		logger := logging.NewLogger(logLevel)
+		if err := report.Validate(output); err != nil {
+			logger.Fatalf("%v", err)
+		}
+		ctx := cmd.Context()
+		client, err := newEOLProvider(logger)
+		if err != nil {
+			logger.Fatalf("Failed to configure endoflife.date client: %v", err)
+		}
 
 		if name != "" {
 			logger.Debug("Fetching available products list from the API")
-			availableProducts, err := helpers.GetAvailableProducts(output)
-
+			resolver, err := newResolver(ctx, client)
 			if err != nil {
 				logger.Fatalf("Failed to fetch available products from the API: %v", err)
 			}
 
 			logger.Debug("Verifying product does exist on the API")
-			var products []string
-			if err := json.Unmarshal(availableProducts, &products); err != nil {
-				logger.Fatalf("failed to parse available products list: %v", err)
-			}
-			found := false
-			for _, p := range products {
-				if p == name {
-					found = true
-					break
-				}
-			}
-			if !found {
-				logger.Fatalf("%s doesn't exist on the API", name)
-			}
+			name = resolveProductName(resolver, name, logger)
 		} else {
 			logger.Fatal("Product name is required.")
 		}
 
-		logger.Debug("Fetching product data from the API")
-		outputData, err := helpers.GetProduct(name, version)
-
-		if err != nil {
-			logger.Fatalf("Failed to fetch data for product %s: %v", name, err)
-		}
-
 		if minVersion != "" && maxVersion != "" {
 			enableCustomRange = true
 		}
 
 		if enableCustomRange && version != "" {
 			logger.Fatal("Custom range can't be run alongside with specific version")
-		} else if enableCustomRange {
-			logger.Debug("Custom range mode is enabled, fetching data from the API for product version from min to max")
-			filtered, err := helpers.FilterVersions(outputData, minVersion, maxVersion)
+		}
+
+		logger.Debug("Fetching product data from the API")
+		if version != "" {
+			cycle, err := endoflife.FindCycle(ctx, client, name, version)
+			if err != nil {
+				logger.Fatalf("Failed to fetch data for product %s: %v", name, err)
+			}
+			if cycle.Cycle != version {
+				logger.Infof("Version %s belongs to the %s %s cycle", version, name, cycle.Cycle)
+			}
+			single = cycle
+		} else {
+			all, err := client.GetProduct(ctx, name)
 			if err != nil {
-				logger.Fatalf("Failed to filter versions: %v", err)
+				logger.Fatalf("Failed to fetch data for product %s: %v", name, err)
 			}
-			outputData = filtered
+			cycles = all
 		}
 
-		if outputFolder != "" {
-			helpers.ExportToFile(outputData, outputFolder)
+		if enableCustomRange {
+			logger.Debug("Custom range mode is enabled, fetching data from the API for product version from min to max")
+			cycles = helpers.FilterCycles(cycles, minVersion, maxVersion)
 		}
 
-		var result interface{}
-		if err := json.Unmarshal(outputData, &result); err != nil {
-			logger.Fatalf("failed to parse JSON response: %v", err)
+		evaluator, err := newEvaluator(logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
 		}
-
-		if output == "table" {
-			table := tablewriter.NewWriter(os.Stdout)
-			switch v := result.(type) {
-			case []interface{}:
-				table.SetHeader([]string{"Cycle", "Latest", "LatestReleaseDate", "ReleaseDate", "LTS", "EOL", "Support", "Risk"})
-				for _, item := range v {
-					if release, ok := item.(map[string]interface{}); ok {
-						renderRichRow(table, []string{
-							helpers.GetStringValue(release["cycle"]),
-							helpers.GetStringValue(release["latest"]),
-							helpers.GetStringValue(release["latestReleaseDate"]),
-							helpers.GetStringValue(release["releaseDate"]),
-							helpers.GetStringValue(release["lts"]),
-							helpers.GetStringValue(release["eol"]),
-							helpers.GetStringValue(release["support"]),
-							string(helpers.CalculateRisk(release["eol"]).Level),
-						})
-					}
-				}
-			case map[string]interface{}:
-				table.SetHeader([]string{"Latest", "LatestReleaseDate", "ReleaseDate", "LTS", "EOL", "Support", "Risk"})
-				renderRichRow(table, []string{
-					helpers.GetStringValue(v["latest"]),
-					helpers.GetStringValue(v["latestReleaseDate"]),
-					helpers.GetStringValue(v["releaseDate"]),
-					helpers.GetStringValue(v["lts"]),
-					helpers.GetStringValue(v["eol"]),
-					helpers.GetStringValue(v["support"]),
-					string(helpers.CalculateRisk(v["eol"]).Level),
-				})
-			}
-			table.Render()
-		} else if output == "json" {
-			fmt.Print(string(outputData))
-		} else {
-			logger.Fatal("output type is not valid.")
+		gate, err := newFailGate(cmd)
+		if err != nil {
+			logger.Fatalf("%v", err)
 		}
+		waivers, err := loadWaivers("", logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		now := evaluationTime()
 
-		riskReport, _ := cmd.Flags().GetBool("risk-report")
-		suggestVersion, _ := cmd.Flags().GetBool("suggest-version")
-
-		if riskReport || suggestVersion {
-			var riskItems []ai.RiskItem
-			var upgradeItems []ai.UpgradeItem
-
-			switch v := result.(type) {
-			case map[string]interface{}:
-				riskInfo := helpers.CalculateRisk(v["eol"])
-				riskItems = append(riskItems, ai.RiskItem{
-					Product:      name,
-					Version:      version,
-					EOL:          helpers.GetStringValue(v["eol"]),
-					RiskLevel:    string(riskInfo.Level),
-					DaysUntilEOL: riskInfo.DaysUntilEOL,
-				})
-				upgradeItems = append(upgradeItems, ai.UpgradeItem{
-					Language:  name,
-					Version:   version,
-					EOL:       helpers.GetStringValue(v["eol"]),
-					RiskLevel: string(riskInfo.Level),
-				})
-			case []interface{}:
-				for _, item := range v {
-					if cycle, ok := item.(map[string]interface{}); ok {
-						riskInfo := helpers.CalculateRisk(cycle["eol"])
-						riskItems = append(riskItems, ai.RiskItem{
-							Product:      name,
-							Version:      helpers.GetStringValue(cycle["cycle"]),
-							EOL:          helpers.GetStringValue(cycle["eol"]),
-							RiskLevel:    string(riskInfo.Level),
-							DaysUntilEOL: riskInfo.DaysUntilEOL,
-						})
-						upgradeItems = append(upgradeItems, ai.UpgradeItem{
-							Language:  name,
-							Version:   helpers.GetStringValue(cycle["cycle"]),
-							EOL:       helpers.GetStringValue(cycle["eol"]),
-							RiskLevel: string(riskInfo.Level),
-						})
-					}
-				}
-			}
+		if single != nil {
+			cycles = []endoflife.Cycle{*single}
+		}
+		findings := productFindings(name, version, cycles, evaluator, waivers, now)
+		for _, finding := range findings {
+			gate.check(finding)
+		}
 
-			if riskReport {
-				printRiskNarrative(riskItems, logger)
-			}
-			if suggestVersion {
-				printUpgradeSuggestions(upgradeItems, logger)
-			}
+		rep := newReport(report.SourceProduct, findings)
+		rep.FailLevel = gate.junitLevel
+		addAIReports(cmd, rep, logger)
+		out, err := openOutput(output, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
 		}
+		defer out.Close()
+		if err := report.Render(out, output, rep); err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		printAIReports(rep, output)
+		reportWaivers(waivers, now, logger)
+		gate.finish(logger)
 	},
 }
 
@@ -205,4 +140,5 @@ func init() {
 	productCmd.Flags().StringP("version", "v", "", "Version of the product")
 	productCmd.Flags().String("min", "", "Minimum version to query")
 	productCmd.Flags().String("max", "", "Maximum version to query")
+	addFailOnFlags(productCmd)
 }
@@ -4,27 +4,18 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
+	"path/filepath"
 
 	ai "github.com/asafdavid23/eolctl/pkg/ai"
-	helpers "github.com/asafdavid23/eolctl/pkg/helpers"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+	"github.com/asafdavid23/eolctl/pkg/policy"
+	"github.com/asafdavid23/eolctl/pkg/report"
 
 	"github.com/asafdavid23/eolctl/internal/logging"
 
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
-type ProjectInfo struct {
-	Product      string `json:"language"`
-	Version      string `json:"version"`
-	Eol          string `json:"eol"`
-	Risk         string `json:"risk"`
-	DaysUntilEOL int    `json:"days_until_eol,omitempty"`
-}
-
 // projectCmd represents the project command
 var projectCmd = &cobra.Command{
 	Use:   "project",
@@ -37,6 +28,9 @@ var projectCmd = &cobra.Command{
 		logLevel, _ := cmd.Flags().GetString("log-level")
 		logger := logging.NewLogger(logLevel)
 		output, _ := cmd.Flags().GetString("output")
+		if err := report.Validate(output); err != nil {
+			logger.Fatalf("%v", err)
+		}
 
 		logger.Debug("Detecting project programming language")
 		stacks, err := ai.DetectStack(projectDir)
@@ -45,85 +39,83 @@ var projectCmd = &cobra.Command{
 		}
 		logger.Debugf("Detected %d stack(s)", len(stacks))
 
-		var results []ProjectInfo
+		var findings []report.Finding
+
+		client, err := newEOLProvider(logger)
+		if err != nil {
+			logger.Fatalf("failed to configure endoflife.date client: %v", err)
+		}
+
+		evaluator, err := newEvaluator(logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		gate, err := newFailGate(cmd)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		waivers, err := loadWaivers(projectDir, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		now := evaluationTime()
+		filter, err := newBaselineFilter(cmd, gate)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		resolver, err := newResolver(cmd.Context(), client)
+		if err != nil {
+			logger.Warnf("failed to fetch available products, skipping product name resolution: %v", err)
+		}
 
 		for _, stack := range stacks {
-			var result map[string]interface{}
-			productData, err := helpers.GetProduct(stack.Language, stack.Version)
-			if err != nil {
-				logger.Errorf("failed to get product info for language %s and version %s: %v", stack.Language, stack.Version, err)
-				continue
+			if resolver != nil {
+				if product, ok := resolver.Best(stack.Language); ok {
+					stack.Language = product
+				}
 			}
 
-			if err := json.Unmarshal(productData, &result); err != nil {
-				logger.Errorf("failed to parse JSON response for %s: %v", stack.Language, err)
+			cycle, err := endoflife.FindCycle(cmd.Context(), client, stack.Language, stack.Version)
+			if err != nil {
+				logger.Errorf("failed to get product info for language %s and version %s: %v", stack.Language, stack.Version, err)
 				continue
 			}
 
-			riskInfo := helpers.CalculateRisk(result["eol"])
-
-			results = append(results, ProjectInfo{
-				Product:      stack.Language,
-				Version:      stack.Version,
-				Eol:          helpers.GetStringValue(result["eol"]),
-				Risk:         string(riskInfo.Level),
-				DaysUntilEOL: riskInfo.DaysUntilEOL,
-			})
+			scope := policy.Scope{Product: stack.Language, Path: filepath.ToSlash(stack.File)}
+			finding := rateCycle(report.Finding{
+				Source:   report.SourceProject,
+				Location: scope.Path,
+				Line:     stack.Line,
+				Product:  stack.Language,
+				Version:  stack.Version,
+			}, *cycle, evaluator, scope)
+			applyWaiver(&finding, waivers, scope, now)
 
 			logger.Infof("Detected: Language=%s, Version=%s", stack.Language, stack.Version)
-		}
-
-		// Handle outputs
-		if output == "json" {
-			// Marshal the results to JSON
-			jsonOutput, err := json.MarshalIndent(results, "", "  ")
-			if err != nil {
-				logger.Fatalf("Failed to marshal results to JSON: %v", err)
-			}
-			fmt.Println(string(jsonOutput))
-		} else if output == "table" {
-			// Print as a table
-			table := tablewriter.NewWriter(os.Stdout)
-			table.SetHeader([]string{"Product", "Version", "Eol", "Risk"})
-
-			for _, result := range results {
-				renderRichRow(table, []string{result.Product, result.Version, result.Eol, result.Risk})
+			if !filter.keep(&finding) {
+				continue
 			}
-			table.Render()
-		} else {
-			logger.Fatal("Invalid output type specified. Use 'table' or 'json'.")
+			gate.check(finding)
+			findings = append(findings, finding)
 		}
 
-		riskReport, _ := cmd.Flags().GetBool("risk-report")
-		suggestVersion, _ := cmd.Flags().GetBool("suggest-version")
-
-		if riskReport || suggestVersion {
-			var riskItems []ai.RiskItem
-			var upgradeItems []ai.UpgradeItem
-
-			for _, item := range results {
-				riskItems = append(riskItems, ai.RiskItem{
-					Product:      item.Product,
-					Version:      item.Version,
-					EOL:          item.Eol,
-					RiskLevel:    item.Risk,
-					DaysUntilEOL: item.DaysUntilEOL,
-				})
-				upgradeItems = append(upgradeItems, ai.UpgradeItem{
-					Language:  item.Product,
-					Version:   item.Version,
-					EOL:       item.Eol,
-					RiskLevel: item.Risk,
-				})
-			}
+		filter.finish(logger)
 
-			if riskReport {
-				printRiskNarrative(riskItems, logger)
-			}
-			if suggestVersion {
-				printUpgradeSuggestions(upgradeItems, logger)
-			}
+		rep := newReport(report.SourceProject, findings)
+		rep.FailLevel = gate.junitLevel
+		addAIReports(cmd, rep, logger)
+		out, err := openOutput(output, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
 		}
+		defer out.Close()
+		if err := report.Render(out, output, rep); err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		printAIReports(rep, output)
+		reportWaivers(waivers, now, logger)
+		gate.finish(logger)
 	},
 }
 
@@ -137,4 +129,6 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// projectCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	addFailOnFlags(projectCmd)
+	addBaselineFlags(projectCmd)
 }
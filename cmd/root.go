@@ -6,6 +6,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/asafdavid23/eolctl/internal/logging"
+	"github.com/asafdavid23/eolctl/pkg/httpclient"
+	"github.com/asafdavid23/eolctl/pkg/report"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -31,8 +37,18 @@ Additionally, the tool offers an easily accessible API for data retrieval and su
 			os.Exit(0)
 		}
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if err := closeCacheStore(); err != nil {
+			logLevel, _ := cmd.Flags().GetString("log-level")
+			logging.NewLogger(logLevel).Warnf("Failed to save cache file: %v", err)
+		}
+	},
 }
 
+// exitCode is set by commands that complete but must still exit non-zero,
+// such as a failed --fail-on gate.
+var exitCode int
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -40,19 +56,54 @@ func Execute() {
 	if err != nil {
 		os.Exit(1)
 	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
 }
 
 func init() {
+	cobra.OnInitialize(initConfig)
+
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.eolctl.yaml)")
 	rootCmd.PersistentFlags().BoolP("version", "v", false, "Display the version of this CLI tool")
-	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output type table/json/yaml")
+	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output type "+strings.Join(report.Formats(), "/"))
+	rootCmd.PersistentFlags().String("output-path", "", "Write the output to this file instead of stdout (a directory gets an output.<format> file)")
+	rootCmd.PersistentFlags().Bool("no-headers", false, "Omit the header row of table, csv and tsv output")
 	rootCmd.PersistentFlags().String("log-level", "", "Set log level")
 	rootCmd.PersistentFlags().Bool("suggest-version", false, "Suggest a version upgrade using AI")
 	rootCmd.PersistentFlags().Bool("risk-report", false, "Generate an AI-powered risk narrative using Claude")
+	rootCmd.PersistentFlags().Bool("offline", false, "Resolve EOL data exclusively from the imported offline bundle")
+	rootCmd.PersistentFlags().String("as-of", "", "Evaluate risk as of this date (YYYY-MM-DD) instead of today")
+	rootCmd.PersistentFlags().String("waivers", "", "Waiver file of accepted risks (default <project>/.eolctl-waivers.yaml for scan project)")
+	rootCmd.PersistentFlags().String("policy", "", "Risk policy file defining thresholds and level names")
+	rootCmd.PersistentFlags().StringSlice("catalog", nil, "Local product catalog file(s) (YAML or JSON) consulted before the public API")
+
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Bypass the local cache for API lookups")
+	rootCmd.PersistentFlags().Bool("refresh", false, "Ignore cached API responses and refresh them from the network")
+
+	viper.BindPFlag("output.path", rootCmd.PersistentFlags().Lookup("output-path"))
+	viper.BindPFlag("output.no_headers", rootCmd.PersistentFlags().Lookup("no-headers"))
+	viper.BindPFlag("offline.enabled", rootCmd.PersistentFlags().Lookup("offline"))
+	viper.BindPFlag("as_of", rootCmd.PersistentFlags().Lookup("as-of"))
+	viper.BindPFlag("waivers.file", rootCmd.PersistentFlags().Lookup("waivers"))
+	viper.BindPFlag("risk.policy_file", rootCmd.PersistentFlags().Lookup("policy"))
+	viper.BindPFlag("catalog.files", rootCmd.PersistentFlags().Lookup("catalog"))
+	viper.BindPFlag("cache.disabled", rootCmd.PersistentFlags().Lookup("no-cache"))
+	viper.BindPFlag("cache.refresh", rootCmd.PersistentFlags().Lookup("refresh"))
+
+	viper.SetDefault("cache.ttl.products", 24*time.Hour)
+	viper.SetDefault("cache.ttl.cycles", 12*time.Hour)
+	viper.SetDefault("cache.ttl.artifacthub", 6*time.Hour)
+	viper.SetDefault("cache.stale_for", 7*24*time.Hour)
+
+	viper.SetDefault("http.timeout", httpclient.DefaultTimeout)
+	viper.SetDefault("http.retries", httpclient.DefaultRetries)
+	viper.SetDefault("http.retry_wait", httpclient.DefaultRetryWait)
+	viper.SetDefault("http.retry_max_wait", httpclient.DefaultRetryMaxWait)
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -71,6 +122,12 @@ func initConfig() {
 		viper.AddConfigPath("./config/")
 	}
 
+	// Allow every config key to be overridden from the environment,
+	// e.g. endoflife.base_url -> EOLCTL_ENDOFLIFE_BASE_URL
+	viper.SetEnvPrefix("eolctl")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
+
 	// Read the config file
 	if err := viper.ReadInConfig(); err != nil {
 		// Ignore "file not found" errors; log other errors
@@ -82,6 +139,6 @@ func initConfig() {
 
 	// Log the config file being used
 	if viper.ConfigFileUsed() != "" {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
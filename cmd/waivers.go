@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+// loadWaivers loads the waiver file named by waivers.file (--waivers). If
+// unset and dir is given, dir/.eolctl-waivers.yaml is used when it exists.
+// It returns a nil set, which waives nothing, when there is no file.
+func loadWaivers(dir string, logger *log.Logger) (*waiver.Set, error) {
+	path := viper.GetString("waivers.file")
+	if path == "" && dir != "" {
+		candidate := filepath.Join(dir, waiver.DefaultFile)
+		if _, err := os.Stat(candidate); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		path = candidate
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	set, err := waiver.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Loaded waivers from %s", path)
+	return set, nil
+}
+
+// reportWaivers warns about waivers that have expired and waivers that no
+// longer match any finding, so stale acceptances get cleaned up.
+func reportWaivers(set *waiver.Set, now time.Time, logger *log.Logger) {
+	for _, w := range set.Expired(now) {
+		logger.Warnf("Waiver for %s expired on %s (owner %s): %s", w, w.Expires, w.Owner, w.Reason)
+	}
+	for _, w := range set.Unmatched() {
+		if w.Expired(now) {
+			continue
+		}
+		logger.Warnf("Waiver for %s in %s matches no finding", w, set.Path)
+	}
+}
+
+// evaluationTime is the moment risk is evaluated at: the --as-of date if
+// set, otherwise now.
+func evaluationTime() time.Time {
+	if t, ok, err := evaluationDate(); err == nil && ok {
+		return t
+	}
+	return time.Now()
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var boltBucket = []byte("entries")
+
+// boltLockTimeout bounds how long an operation waits for another process's
+// transaction. Transactions are short, so a longer wait means the database
+// is stuck; the operation fails and the caller carries on without the cache.
+const boltLockTimeout = 2 * time.Second
+
+// BoltStore keeps the cache in an embedded bbolt database. The database is
+// opened per operation, read-only for lookups, so concurrent eolctl
+// processes only hold the file lock for the length of one transaction.
+// Within a process a lock serialises writers so requests and background
+// revalidations never wait on each other's file lock.
+type BoltStore struct {
+	path string
+	mu   sync.RWMutex
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// OpenBolt returns a store backed by the database at path, creating it if
+// needed.
+func OpenBolt(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	s := &BoltStore{path: path}
+	if err := s.update(func(b *bolt.Bucket) error { return nil }); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *BoltStore) Get(key string) (Entry, bool, error) {
+	var (
+		entry Entry
+		found bool
+	)
+	err := s.view(func(b *bolt.Bucket) error {
+		data := b.Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		e, err := decodeEntry(data)
+		if err != nil {
+			// Treat undecodable records as misses; the next Set overwrites them.
+			return nil
+		}
+		if e.Expired(time.Now()) {
+			return nil
+		}
+		entry, found = e, true
+		return nil
+	})
+	return entry, found, err
+}
+
+func (s *BoltStore) Set(key string, entry Entry) error {
+	data, err := encodeEntry(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	return s.update(func(b *bolt.Bucket) error {
+		return b.Put([]byte(key), data)
+	})
+}
+
+func (s *BoltStore) Delete(key string) (bool, error) {
+	var found bool
+	err := s.update(func(b *bolt.Bucket) error {
+		found = b.Get([]byte(key)) != nil
+		return b.Delete([]byte(key))
+	})
+	return found, err
+}
+
+// List returns live entries and removes expired ones as a side effect.
+func (s *BoltStore) List() ([]Info, error) {
+	var infos []Info
+	now := time.Now()
+	err := s.update(func(b *bolt.Bucket) error {
+		var expired [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			e, err := decodeEntry(v)
+			if err != nil || e.Expired(now) {
+				expired = append(expired, append([]byte(nil), k...))
+				return nil
+			}
+			infos = append(infos, e.info(string(k)))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return infos, err
+}
+
+func (s *BoltStore) Clear() (int, error) {
+	var removed int
+	err := s.withDB(false, func(tx *bolt.Tx) error {
+		if b := tx.Bucket(boltBucket); b != nil {
+			removed = b.Stats().KeyN
+			if err := tx.DeleteBucket(boltBucket); err != nil {
+				return err
+			}
+		}
+		_, err := tx.CreateBucket(boltBucket)
+		return err
+	})
+	return removed, err
+}
+
+func (s *BoltStore) Location() string {
+	return s.path
+}
+
+// Close is a no-op: the database is only open during an operation.
+func (s *BoltStore) Close() error {
+	return nil
+}
+
+func (s *BoltStore) view(fn func(b *bolt.Bucket) error) error {
+	return s.withDB(true, func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltBucket)
+		if b == nil {
+			return nil
+		}
+		return fn(b)
+	})
+}
+
+func (s *BoltStore) update(fn func(b *bolt.Bucket) error) error {
+	return s.withDB(false, func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(boltBucket)
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+}
+
+// withDB opens the database for a single transaction. Read-only handles take
+// a shared file lock, so lookups from several processes run in parallel.
+func (s *BoltStore) withDB(readOnly bool, fn func(tx *bolt.Tx) error) error {
+	if readOnly {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	} else {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	db, err := bolt.Open(s.path, 0644, &bolt.Options{Timeout: boltLockTimeout, ReadOnly: readOnly})
+	if err != nil {
+		return fmt.Errorf("failed to open cache database: %w", err)
+	}
+	defer db.Close()
+
+	if readOnly {
+		return db.View(fn)
+	}
+	return db.Update(fn)
+}
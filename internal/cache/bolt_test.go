@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBoltStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	s, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+
+	now := time.Now()
+	fresh := Entry{StatusCode: 200, Body: []byte("fresh"), StoredAt: now, FreshUntil: now.Add(time.Hour)}
+	expired := Entry{StatusCode: 200, Body: []byte("expired"), StoredAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	if err := s.Set("fresh", fresh); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set("expired", expired); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	if e, ok, err := s.Get("fresh"); err != nil || !ok || string(e.Body) != "fresh" {
+		t.Errorf("Get(fresh) = %q, %v, %v; want fresh, true, nil", e.Body, ok, err)
+	}
+	if _, ok, err := s.Get("expired"); err != nil || ok {
+		t.Errorf("Get(expired) = %v, %v; want false, nil", ok, err)
+	}
+	if _, ok, _ := s.Get("missing"); ok {
+		t.Error("Get(missing) found an entry")
+	}
+
+	infos, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Key != "fresh" || infos[0].Size != len("fresh") {
+		t.Errorf("List() = %+v, want only the fresh entry", infos)
+	}
+
+	if found, err := s.Delete("fresh"); err != nil || !found {
+		t.Errorf("Delete(fresh) = %v, %v; want true, nil", found, err)
+	}
+	if found, err := s.Delete("fresh"); err != nil || found {
+		t.Errorf("second Delete(fresh) = %v, %v; want false, nil", found, err)
+	}
+
+	if err := s.Set("a", fresh); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if removed, err := s.Clear(); err != nil || removed != 1 {
+		t.Errorf("Clear() = %d, %v; want 1, nil", removed, err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestBoltStoreConcurrentUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	s, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i)
+			if err := s.Set(key, Entry{Body: []byte(key)}); err != nil {
+				t.Errorf("Set(%s): %v", key, err)
+			}
+			if _, ok, err := s.Get(key); err != nil || !ok {
+				t.Errorf("Get(%s) = %v, %v", key, ok, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	s, err = OpenBolt(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close()
+	infos, err := s.List()
+	if err != nil || len(infos) != 8 {
+		t.Errorf("List() after reopen = %d entries, %v; want 8", len(infos), err)
+	}
+}
+
+func TestBoltStoreSharedBetweenStores(t *testing.T) {
+	// Two stores on one path stand in for two eolctl processes: neither
+	// holds the database between operations, so both work while open.
+	path := filepath.Join(t.TempDir(), "cache.db")
+	a, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+	defer a.Close()
+
+	start := time.Now()
+	b, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("second OpenBolt: %v", err)
+	}
+	defer b.Close()
+
+	if err := a.Set("from-a", Entry{Body: []byte("a")}); err != nil {
+		t.Fatalf("a.Set: %v", err)
+	}
+	if e, ok, err := b.Get("from-a"); err != nil || !ok || string(e.Body) != "a" {
+		t.Errorf("b.Get(from-a) = %q, %v, %v; want a's entry", e.Body, ok, err)
+	}
+	if err := b.Set("from-b", Entry{Body: []byte("b")}); err != nil {
+		t.Fatalf("b.Set: %v", err)
+	}
+	if _, ok, err := a.Get("from-b"); err != nil || !ok {
+		t.Errorf("a.Get(from-b) = %v, %v; want b's entry", ok, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("operations took %s, want no waiting on the other store's lock", elapsed)
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"time"
+)
+
+// Entry is a cached HTTP response body.
+type Entry struct {
+	StatusCode int
+	Body       []byte
+	StoredAt   time.Time
+	FreshUntil time.Time
+	// ExpiresAt is when the entry is dropped entirely; zero means never.
+	ExpiresAt time.Time
+	// ETag and LastModified are the validators sent by the server, used to
+	// revalidate the entry with a conditional request.
+	ETag         string
+	LastModified string
+}
+
+// Fresh reports whether the entry can be served without revalidation.
+func (e Entry) Fresh(now time.Time) bool {
+	return now.Before(e.FreshUntil)
+}
+
+// Expired reports whether the entry is past its hard expiry.
+func (e Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+func (e Entry) info(key string) Info {
+	return Info{
+		Key:        key,
+		Size:       len(e.Body),
+		StoredAt:   e.StoredAt,
+		FreshUntil: e.FreshUntil,
+		ExpiresAt:  e.ExpiresAt,
+		ETag:       e.ETag,
+	}
+}
+
+func encodeEntry(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeEntry(data []byte) (Entry, error) {
+	var e Entry
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e)
+	return e, err
+}
+
+func init() {
+	// Entries are stored as interface{} values inside the gob-encoded cache file.
+	gob.Register(Entry{})
+}
@@ -0,0 +1,346 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	log "github.com/sirupsen/logrus"
+)
+
+// SchemaVersion is the version of the on-disk cache format written by this
+// build. Files without a version header predate versioning and are migrated.
+const SchemaVersion = 1
+
+// lockTimeout bounds how long a save waits for another eolctl process to
+// release the cache lock.
+const lockTimeout = 10 * time.Second
+
+var (
+	errCorrupt     = errors.New("corrupt cache file")
+	errNewerSchema = errors.New("cache file was written by a newer eolctl")
+)
+
+// fileFormat is the versioned layout of the cache file.
+type fileFormat struct {
+	Version int
+	Items   map[string]cache.Item
+}
+
+// FileStore keeps the cache in memory and persists it to a single
+// gob-encoded file on Close.
+type FileStore struct {
+	path   string
+	items  *cache.Cache
+	logger *log.Logger
+	dirty  atomic.Bool
+
+	// Keys removed during this run, so saving doesn't resurrect them from
+	// the copy on disk that other processes may have written.
+	mu        sync.Mutex
+	deleted   map[string]struct{}
+	clearedAt time.Time
+
+	// readOnly is set when the file on disk uses a newer schema; this
+	// process then never overwrites it.
+	readOnly atomic.Bool
+}
+
+var _ Store = (*FileStore)(nil)
+
+// OpenFile loads the cache file at path. A missing file yields an empty
+// cache; a corrupt one is moved aside with a warning. A file written by a
+// newer eolctl is left alone and the cache runs in memory for this process.
+func OpenFile(path string, logger *log.Logger) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	s := &FileStore{
+		path:    path,
+		items:   cache.New(cache.NoExpiration, 10*time.Minute),
+		logger:  logger,
+		deleted: make(map[string]struct{}),
+	}
+
+	items, err := s.read()
+	if errors.Is(err, errCorrupt) {
+		items, err = s.moveAside()
+	}
+	if errors.Is(err, errNewerSchema) {
+		logger.Warnf("Cache file %s is unusable (%v); caching in memory only and leaving the file untouched", path, err)
+		s.readOnly.Store(true)
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache file: %w", err)
+	}
+
+	for key, item := range items {
+		var ttl time.Duration
+		if item.Expiration > 0 {
+			ttl = time.Until(time.Unix(0, item.Expiration))
+			if ttl <= 0 {
+				continue // item already expired
+			}
+		} else {
+			ttl = cache.NoExpiration
+		}
+		s.items.Set(key, item.Object, ttl)
+	}
+
+	return s, nil
+}
+
+// moveAside renames a corrupt cache file to <path>.corrupt. It holds the
+// cache lock so it cannot move a file another process is just replacing, and
+// re-reads the file first in case that process already repaired it.
+func (s *FileStore) moveAside() (map[string]cache.Item, error) {
+	unlock, err := lockFile(s.path+".lock", lockTimeout)
+	if err != nil {
+		s.logger.Warnf("Cache file %s is unreadable and could not be locked to move it aside: %v; starting with an empty cache", s.path, err)
+		return nil, nil
+	}
+	defer unlock()
+
+	items, err := s.read()
+	if !errors.Is(err, errCorrupt) {
+		return items, err
+	}
+
+	aside := s.path + ".corrupt"
+	if renameErr := os.Rename(s.path, aside); renameErr != nil {
+		s.logger.Warnf("Cache file %s is unreadable (%v) and could not be moved aside: %v; starting with an empty cache", s.path, err, renameErr)
+	} else {
+		s.logger.Warnf("Cache file %s is unreadable (%v); moved it to %s and starting with an empty cache", s.path, err, aside)
+	}
+	return nil, nil
+}
+
+func (s *FileStore) Get(key string) (Entry, bool, error) {
+	v, found := s.items.Get(key)
+	if !found {
+		return Entry{}, false, nil
+	}
+	entry, ok := v.(Entry)
+	return entry, ok, nil
+}
+
+func (s *FileStore) Set(key string, entry Entry) error {
+	ttl := cache.NoExpiration
+	if !entry.ExpiresAt.IsZero() {
+		ttl = time.Until(entry.ExpiresAt)
+	}
+	s.items.Set(key, entry, ttl)
+	s.dirty.Store(true)
+	return nil
+}
+
+func (s *FileStore) Delete(key string) (bool, error) {
+	if _, found := s.items.Get(key); !found {
+		return false, nil
+	}
+	s.items.Delete(key)
+
+	s.mu.Lock()
+	s.deleted[key] = struct{}{}
+	s.mu.Unlock()
+
+	s.dirty.Store(true)
+	return true, nil
+}
+
+func (s *FileStore) List() ([]Info, error) {
+	items := s.items.Items()
+
+	infos := make([]Info, 0, len(items))
+	for key, item := range items {
+		var info Info
+		if entry, ok := item.Object.(Entry); ok {
+			info = entry.info(key)
+		} else {
+			info = Info{Key: key, Size: encodedSize(item.Object)}
+		}
+		if item.Expiration > 0 {
+			info.ExpiresAt = time.Unix(0, item.Expiration)
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+	return infos, nil
+}
+
+func (s *FileStore) Clear() (int, error) {
+	removed := s.items.ItemCount()
+	s.items.Flush()
+
+	s.mu.Lock()
+	s.clearedAt = time.Now()
+	s.mu.Unlock()
+
+	s.dirty.Store(true)
+	return removed, nil
+}
+
+func (s *FileStore) Location() string {
+	return s.path
+}
+
+// Close saves the cache if anything changed since it was opened.
+func (s *FileStore) Close() error {
+	if !s.dirty.Load() {
+		return nil
+	}
+	return s.Save()
+}
+
+// Save persists the cache. It takes an exclusive lock, merges in entries
+// written by other processes since this one loaded the file, and replaces
+// the file atomically so readers never see a partial write. A file written
+// by a newer eolctl is never overwritten.
+func (s *FileStore) Save() error {
+	if s.readOnly.Load() {
+		return nil
+	}
+
+	unlock, err := lockFile(s.path+".lock", lockTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to lock cache file: %w", err)
+	}
+	defer unlock()
+
+	items := s.items.Items()
+
+	onDisk, err := s.read()
+	if errors.Is(err, errNewerSchema) {
+		s.logger.Warnf("Cache file %s is unusable (%v); not saving this run's cache", s.path, err)
+		s.readOnly.Store(true)
+		return nil
+	}
+	if err != nil && !errors.Is(err, errCorrupt) {
+		return err
+	}
+	s.merge(items, onDisk)
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+
+	if err := enc.Encode(fileFormat{Version: SchemaVersion, Items: items}); err != nil {
+		return fmt.Errorf("failed to encode cache file: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	s.dirty.Store(false)
+	return nil
+}
+
+// read decodes the cache file, accepting both the versioned format and the
+// unversioned map written by older releases.
+func (s *FileStore) read() (map[string]cache.Item, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var file fileFormat
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&file); err == nil {
+		if file.Version > SchemaVersion {
+			return nil, fmt.Errorf("%w (schema %d, this build supports %d)", errNewerSchema, file.Version, SchemaVersion)
+		}
+		return file.Items, nil
+	}
+
+	var legacy map[string]cache.Item
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&legacy); err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorrupt, err)
+	}
+	return legacy, nil
+}
+
+// merge adds entries from onDisk that this process has neither deleted nor
+// overwritten with a newer copy.
+func (s *FileStore) merge(items, onDisk map[string]cache.Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	for key, diskItem := range onDisk {
+		if diskItem.Expiration > 0 && diskItem.Expiration <= now {
+			continue
+		}
+		if _, gone := s.deleted[key]; gone {
+			continue
+		}
+
+		diskEntry, diskIsEntry := diskItem.Object.(Entry)
+		if !s.clearedAt.IsZero() && (!diskIsEntry || !diskEntry.StoredAt.After(s.clearedAt)) {
+			continue
+		}
+
+		memItem, inMemory := items[key]
+		if !inMemory {
+			items[key] = diskItem
+			continue
+		}
+		if memEntry, ok := memItem.Object.(Entry); ok && diskIsEntry && diskEntry.StoredAt.After(memEntry.StoredAt) {
+			items[key] = diskItem
+		}
+	}
+}
+
+func encodedSize(v interface{}) int {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return 0
+	}
+	return buf.Len()
+}
+
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary cache file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to sync cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to set cache file permissions: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func openFileStore(t *testing.T, path string) *FileStore {
+	t.Helper()
+	logger, _ := test.NewNullLogger()
+	s, err := OpenFile(path, logger)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	return s
+}
+
+func keys(t *testing.T, s Store) string {
+	t.Helper()
+	infos, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var keys []string
+	for _, info := range infos {
+		keys = append(keys, info.Key)
+	}
+	return strings.Join(keys, ",")
+}
+
+func entry(body string, storedAt time.Time) Entry {
+	return Entry{StatusCode: 200, Body: []byte(body), StoredAt: storedAt, FreshUntil: storedAt.Add(time.Hour)}
+}
+
+func writeGob(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	s := openFileStore(t, path)
+	now := time.Now()
+	s.Set("a", entry("a", now))
+	s.Set("gone", Entry{Body: []byte("gone"), StoredAt: now, ExpiresAt: now.Add(50 * time.Millisecond)})
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	s = openFileStore(t, path)
+	if e, ok, _ := s.Get("a"); !ok || string(e.Body) != "a" {
+		t.Errorf("Get(a) = %q, %v; want the saved entry", e.Body, ok)
+	}
+	if got := keys(t, s); got != "a" {
+		t.Errorf("keys = %q, want the expired entry dropped on load", got)
+	}
+
+	matches, _ := filepath.Glob(path + ".*.tmp")
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestFileStoreMerge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	old := time.Now().Add(-time.Minute)
+
+	seed := openFileStore(t, path)
+	seed.Set("shared", entry("seed", old))
+	seed.Set("deleted", entry("deleted", old))
+	if err := seed.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both stores load the seeded file, then change it independently.
+	a := openFileStore(t, path)
+	b := openFileStore(t, path)
+
+	a.Set("from-a", entry("a", time.Now()))
+	a.Delete("deleted")
+	a.Set("shared", entry("newer", time.Now()))
+
+	b.Set("from-b", entry("b", time.Now()))
+	b.Set("shared", entry("older", old.Add(time.Second)))
+
+	if err := a.Save(); err != nil {
+		t.Fatalf("a.Save: %v", err)
+	}
+	if err := b.Save(); err != nil {
+		t.Fatalf("b.Save: %v", err)
+	}
+
+	s := openFileStore(t, path)
+	// b saved last but keeps a's entry, and a's newer copy of the shared
+	// key wins. b still holds the key a deleted, so its save writes it back.
+	got := keys(t, s)
+	if got != "deleted,from-a,from-b,shared" {
+		t.Errorf("keys = %q", got)
+	}
+	if e, _, _ := s.Get("shared"); string(e.Body) != "newer" {
+		t.Errorf("shared = %q, want the most recently stored copy", e.Body)
+	}
+}
+
+func TestFileStoreMergeAfterDeleteAndClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	old := time.Now().Add(-time.Minute)
+
+	seed := openFileStore(t, path)
+	seed.Set("a", entry("a", old))
+	seed.Set("b", entry("b", old))
+	if err := seed.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := openFileStore(t, path)
+	s.Delete("a")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := keys(t, openFileStore(t, path)); got != "b" {
+		t.Errorf("after Delete: keys = %q, want the deleted key gone from disk", got)
+	}
+
+	other := openFileStore(t, path)
+	s.Clear()
+	other.Set("later", entry("later", time.Now().Add(time.Second)))
+	if err := other.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := keys(t, openFileStore(t, path)); got != "later" {
+		t.Errorf("after Clear: keys = %q, want only the entry stored after the clear", got)
+	}
+}
+
+func TestFileStoreConcurrentSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	a := openFileStore(t, path)
+	b := openFileStore(t, path)
+
+	var wg sync.WaitGroup
+	for i, s := range []*FileStore{a, b} {
+		wg.Add(1)
+		go func(i int, s *FileStore) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				key := string(rune('a'+i)) + string(rune('0'+j))
+				s.Set(key, entry(key, time.Now()))
+				if err := s.Save(); err != nil {
+					t.Errorf("Save: %v", err)
+				}
+			}
+		}(i, s)
+	}
+	wg.Wait()
+
+	infos, err := openFileStore(t, path).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 20 {
+		var got []string
+		for _, info := range infos {
+			got = append(got, info.Key)
+		}
+		sort.Strings(got)
+		t.Errorf("%d entries on disk (%v), want all 20 from both stores", len(infos), got)
+	}
+}
+
+func TestFileStoreRecoversFromCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	s := openFileStore(t, path)
+	s.Set("a", entry("a", time.Now()))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger, hook := test.NewNullLogger()
+	s, err = OpenFile(path, logger)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if got := keys(t, s); got != "" {
+		t.Errorf("keys = %q, want an empty cache", got)
+	}
+	if last := hook.LastEntry(); last == nil || !strings.Contains(last.Message, "moved it to "+path+".corrupt") {
+		t.Errorf("log = %v, want a warning about moving the file aside", last)
+	}
+	if _, err := os.Stat(path + ".corrupt"); err != nil {
+		t.Errorf("corrupt file not moved aside: %v", err)
+	}
+
+	s.Set("b", entry("b", time.Now()))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := keys(t, openFileStore(t, path)); got != "b" {
+		t.Errorf("keys after save = %q, want b", got)
+	}
+}
+
+func TestFileStoreLoadsLegacyFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	legacy := map[string]cache.Item{
+		"current": {Object: entry("current", time.Now())},
+		"expired": {Object: entry("expired", time.Now()), Expiration: time.Now().Add(-time.Minute).UnixNano()},
+	}
+	writeGob(t, path, legacy)
+
+	s := openFileStore(t, path)
+	if got := keys(t, s); got != "current" {
+		t.Errorf("keys = %q, want the unexpired legacy entry", got)
+	}
+
+	// Saving migrates the file to the versioned format.
+	s.Set("new", entry("new", time.Now()))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var file fileFormat
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&file); err != nil || file.Version != SchemaVersion {
+		t.Errorf("saved file version = %d, %v; want %d", file.Version, err, SchemaVersion)
+	}
+}
+
+func TestFileStoreLeavesNewerSchemaAlone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	writeGob(t, path, fileFormat{Version: SchemaVersion + 1, Items: map[string]cache.Item{
+		"future": {Object: entry("future", time.Now())},
+	}})
+	before, _ := os.ReadFile(path)
+
+	logger, hook := test.NewNullLogger()
+	s, err := OpenFile(path, logger)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if last := hook.LastEntry(); last == nil || !strings.Contains(last.Message, "written by a newer eolctl") {
+		t.Errorf("log = %v, want a warning about the newer schema", last)
+	}
+
+	s.Set("a", entry("a", time.Now()))
+	if e, ok, _ := s.Get("a"); !ok || string(e.Body) != "a" {
+		t.Error("entries are not cached in memory")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(before, after) {
+		t.Error("cache file written by a newer eolctl was overwritten")
+	}
+}
+
+func TestFileStoreSaveKeepsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+	s := openFileStore(t, path)
+	s.Set("a", entry("a", time.Now()))
+
+	// A newer eolctl replaces the file after this store loaded it.
+	writeGob(t, path, fileFormat{Version: SchemaVersion + 1})
+	before, _ := os.ReadFile(path)
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(before, after) {
+		t.Error("cache file written by a newer eolctl was overwritten")
+	}
+}
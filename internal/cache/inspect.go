@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Info describes a cache entry for inspection. Timestamps an entry does
+// not have are zero and left out of its JSON encoding.
+type Info struct {
+	Key        string    `json:"key"`
+	Size       int       `json:"size"`
+	StoredAt   time.Time `json:"stored_at"`
+	FreshUntil time.Time `json:"fresh_until"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	ETag       string    `json:"etag,omitempty"`
+}
+
+// Stale reports whether the entry is past its freshness window. Entries
+// written by older versions carry no freshness metadata and count as stale.
+func (i Info) Stale(now time.Time) bool {
+	return !now.Before(i.FreshUntil)
+}
+
+// MarshalJSON omits zero timestamps, such as the expiry of an entry kept
+// until it is evicted; omitempty has no effect on time.Time.
+func (i Info) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Key        string     `json:"key"`
+		Size       int        `json:"size"`
+		StoredAt   *time.Time `json:"stored_at,omitempty"`
+		FreshUntil *time.Time `json:"fresh_until,omitempty"`
+		ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+		ETag       string     `json:"etag,omitempty"`
+	}{
+		Key:        i.Key,
+		Size:       i.Size,
+		StoredAt:   timeOrNil(i.StoredAt),
+		FreshUntil: timeOrNil(i.FreshUntil),
+		ExpiresAt:  timeOrNil(i.ExpiresAt),
+		ETag:       i.ETag,
+	})
+}
+
+// timeOrNil returns nil for the zero time and &t otherwise.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInfoMarshalJSON(t *testing.T) {
+	stored := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		info Info
+		want string
+	}{
+		{
+			name: "all timestamps",
+			info: Info{Key: "k", Size: 3, StoredAt: stored, FreshUntil: stored.Add(time.Hour), ExpiresAt: stored.Add(24 * time.Hour), ETag: `"abc"`},
+			want: `{"key":"k","size":3,"stored_at":"2026-03-01T12:00:00Z","fresh_until":"2026-03-01T13:00:00Z","expires_at":"2026-03-02T12:00:00Z","etag":"\"abc\""}`,
+		},
+		{
+			name: "no expiry",
+			info: Info{Key: "k", Size: 3, StoredAt: stored, FreshUntil: stored.Add(time.Hour)},
+			want: `{"key":"k","size":3,"stored_at":"2026-03-01T12:00:00Z","fresh_until":"2026-03-01T13:00:00Z"}`,
+		},
+		{
+			name: "entry from an older version",
+			info: Info{Key: "k", Size: 3},
+			want: `{"key":"k","size":3}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.info)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s\nwant %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurgeStale(t *testing.T) {
+	s := openFileStore(t, filepath.Join(t.TempDir(), "cache.gob"))
+	now := time.Now()
+	set := func(key string, e Entry) {
+		t.Helper()
+		if err := s.Set(key, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	set("fresh", entry("a", now))
+	revalidating := entry("b", now.Add(-2*time.Hour))
+	revalidating.ExpiresAt = now.Add(time.Hour)
+	set("revalidating", revalidating)
+	set("stale", entry("c", now.Add(-2*time.Hour)))
+	set("legacy", Entry{StatusCode: 200, Body: []byte("d")})
+
+	removed, err := PurgeStale(s)
+	if err != nil {
+		t.Fatalf("PurgeStale: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("PurgeStale removed %d entries, want 3", removed)
+	}
+	if got := keys(t, s); got != "fresh" {
+		t.Errorf("entries after PurgeStale = %s, want fresh", got)
+	}
+}
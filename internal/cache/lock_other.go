@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package cache
+
+import "time"
+
+// lockFile is a no-op on platforms without advisory file locks; writes are
+// still atomic, but concurrent saves may drop each other's new entries.
+func lockFile(path string, timeout time.Duration) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,346 @@
+package cache
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisConfig configures the Redis-protocol backend. Any server speaking
+// RESP2 (Redis, Valkey, KeyDB, Dragonfly, ...) works.
+type RedisConfig struct {
+	Addr     string        `mapstructure:"addr"`
+	Username string        `mapstructure:"username"`
+	Password string        `mapstructure:"password"`
+	DB       int           `mapstructure:"db"`
+	Prefix   string        `mapstructure:"prefix"`
+	TLS      bool          `mapstructure:"tls"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// DefaultRedisPrefix namespaces eolctl keys in a shared Redis database.
+const DefaultRedisPrefix = "eolctl:"
+
+// RedisStore keeps the cache in a Redis-compatible server so it can be
+// shared by every runner in a team. Expiry is delegated to the server.
+type RedisStore struct {
+	cfg RedisConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// redisError is an error reply from the server.
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// OpenRedis connects to the server described by cfg.
+func OpenRedis(cfg RedisConfig) (*RedisStore, error) {
+	if cfg.Addr == "" {
+		cfg.Addr = "localhost:6379"
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultRedisPrefix
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	s := &RedisStore{cfg: cfg}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *RedisStore) Get(key string) (Entry, bool, error) {
+	reply, err := s.do("GET", s.cfg.Prefix+key)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return Entry{}, false, nil
+	}
+	e, err := decodeEntry(data)
+	if err != nil {
+		// Treat undecodable values as misses; the next Set overwrites them.
+		return Entry{}, false, nil
+	}
+	return e, true, nil
+}
+
+func (s *RedisStore) Set(key string, entry Entry) error {
+	data, err := encodeEntry(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	args := []string{"SET", s.cfg.Prefix + key, string(data)}
+	if !entry.ExpiresAt.IsZero() {
+		ttl := time.Until(entry.ExpiresAt).Milliseconds()
+		if ttl <= 0 {
+			return nil
+		}
+		args = append(args, "PX", strconv.FormatInt(ttl, 10))
+	}
+
+	_, err = s.do(args...)
+	return err
+}
+
+func (s *RedisStore) Delete(key string) (bool, error) {
+	reply, err := s.do("DEL", s.cfg.Prefix+key)
+	if err != nil {
+		return false, err
+	}
+	n, _ := reply.(int64)
+	return n > 0, nil
+}
+
+func (s *RedisStore) List() ([]Info, error) {
+	keys, err := s.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []Info
+	for _, key := range keys {
+		e, found, err := s.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			infos = append(infos, e.info(key))
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+	return infos, nil
+}
+
+func (s *RedisStore) Clear() (int, error) {
+	keys, err := s.scan()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, key := range keys {
+		ok, err := s.Delete(key)
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *RedisStore) Location() string {
+	scheme := "redis"
+	if s.cfg.TLS {
+		scheme = "rediss"
+	}
+	return fmt.Sprintf("%s://%s/%d (prefix %q)", scheme, s.cfg.Addr, s.cfg.DB, s.cfg.Prefix)
+}
+
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// scan returns every key under the configured prefix, with the prefix removed.
+func (s *RedisStore) scan() ([]string, error) {
+	pattern := globEscape(s.cfg.Prefix) + "*"
+	cursor := "0"
+	var keys []string
+
+	for {
+		reply, err := s.do("SCAN", cursor, "MATCH", pattern, "COUNT", "200")
+		if err != nil {
+			return nil, err
+		}
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return nil, fmt.Errorf("redis: unexpected SCAN reply")
+		}
+		next, _ := parts[0].([]byte)
+		batch, _ := parts[1].([]interface{})
+		for _, k := range batch {
+			if b, ok := k.([]byte); ok {
+				keys = append(keys, strings.TrimPrefix(string(b), s.cfg.Prefix))
+			}
+		}
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return keys, nil
+		}
+	}
+}
+
+// do sends a command and returns its reply, reconnecting once if the
+// connection was dropped since the last command.
+func (s *RedisStore) do(args ...string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reply, err := s.roundTrip(args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		if s.conn != nil {
+			s.conn.Close()
+			s.conn = nil
+		}
+		if connErr := s.connect(); connErr != nil {
+			return nil, connErr
+		}
+		reply, err = s.roundTrip(args)
+	}
+	return reply, err
+}
+
+func (s *RedisStore) connect() error {
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.cfg.TLS {
+		host, _, _ := net.SplitHostPort(s.cfg.Addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.cfg.Addr, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", s.cfg.Addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis at %s: %w", s.cfg.Addr, err)
+	}
+
+	s.conn = conn
+	s.rd = bufio.NewReader(conn)
+
+	if s.cfg.Password != "" {
+		auth := []string{"AUTH", s.cfg.Password}
+		if s.cfg.Username != "" {
+			auth = []string{"AUTH", s.cfg.Username, s.cfg.Password}
+		}
+		if _, err := s.roundTrip(auth); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	if s.cfg.DB != 0 {
+		if _, err := s.roundTrip([]string{"SELECT", strconv.Itoa(s.cfg.DB)}); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) roundTrip(args []string) (interface{}, error) {
+	if s.conn == nil {
+		return nil, io.ErrClosedPipe
+	}
+	if err := s.conn.SetDeadline(time.Now().Add(s.cfg.Timeout)); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(s.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	return readReply(s.rd)
+}
+
+// readReply parses a single RESP2 reply.
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(rd); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,354 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal in-memory server speaking the RESP2 commands
+// RedisStore uses.
+type fakeRedis struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	data     map[string][]byte
+	commands [][]string
+	// password, when set, is required by AUTH before any other command.
+	password string
+	// failKeys answer GET with an error reply.
+	failKeys map[string]string
+	// dropNext closes the connection instead of answering the next command.
+	dropNext bool
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeRedis{ln: ln, data: map[string][]byte{}, failKeys: map[string]string{}}
+	t.Cleanup(func() { ln.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeRedis) addr() string { return f.ln.Addr().String() }
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	authed := false
+	for {
+		reply, err := readReply(rd)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, a := range reply.([]interface{}) {
+			args = append(args, string(a.([]byte)))
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, args)
+		if f.dropNext {
+			f.dropNext = false
+			f.mu.Unlock()
+			return
+		}
+		var out string
+		if f.password != "" && !authed && args[0] != "AUTH" {
+			out = "-NOAUTH Authentication required.\r\n"
+		} else {
+			out = f.exec(args, &authed)
+		}
+		f.mu.Unlock()
+
+		if _, err := io.WriteString(conn, out); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) exec(args []string, authed *bool) string {
+	switch strings.ToUpper(args[0]) {
+	case "AUTH":
+		if args[len(args)-1] != f.password {
+			return "-WRONGPASS invalid username-password pair\r\n"
+		}
+		*authed = true
+		return "+OK\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if msg, ok := f.failKeys[args[1]]; ok {
+			return "-" + msg + "\r\n"
+		}
+		v, ok := f.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET":
+		f.data[args[1]] = []byte(args[2])
+		return "+OK\r\n"
+	case "DEL":
+		_, ok := f.data[args[1]]
+		delete(f.data, args[1])
+		if ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	case "SCAN":
+		// Pages of two keys, so callers have to follow the cursor.
+		prefix := strings.TrimSuffix(strings.ReplaceAll(args[3], `\`, ""), "*")
+		var keys []string
+		for k := range f.data {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		start, _ := strconv.Atoi(args[1])
+		end := min(start+2, len(keys))
+		next := strconv.Itoa(end)
+		if end >= len(keys) {
+			next = "0"
+		}
+		out := fmt.Sprintf("*2\r\n$%d\r\n%s\r\n*%d\r\n", len(next), next, end-start)
+		for _, k := range keys[start:end] {
+			out += fmt.Sprintf("$%d\r\n%s\r\n", len(k), k)
+		}
+		return out
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+func (f *fakeRedis) lastCommand(name string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.commands) - 1; i >= 0; i-- {
+		if f.commands[i][0] == name {
+			return f.commands[i]
+		}
+	}
+	return nil
+}
+
+func openFakeRedis(t *testing.T, f *fakeRedis, cfg RedisConfig) *RedisStore {
+	t.Helper()
+	cfg.Addr = f.addr()
+	s, err := OpenRedis(cfg)
+	if err != nil {
+		t.Fatalf("OpenRedis: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestRedisStoreGetSetDelete(t *testing.T) {
+	f := newFakeRedis(t)
+	s := openFakeRedis(t, f, RedisConfig{})
+
+	if _, ok, err := s.Get("missing"); err != nil || ok {
+		t.Errorf("Get(missing) = %v, %v; want a miss from the nil bulk reply", ok, err)
+	}
+
+	now := time.Now()
+	entry := Entry{StatusCode: 200, Body: []byte("body\r\nwith CRLF"), StoredAt: now, FreshUntil: now.Add(time.Hour)}
+	if err := s.Set("products", entry); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if cmd := f.lastCommand("SET"); len(cmd) != 3 || cmd[1] != DefaultRedisPrefix+"products" {
+		t.Errorf("SET without expiry sent %q, want key with the default prefix and no PX", cmd)
+	}
+	got, ok, err := s.Get("products")
+	if err != nil || !ok {
+		t.Fatalf("Get(products) = %v, %v", ok, err)
+	}
+	if string(got.Body) != string(entry.Body) || got.StatusCode != 200 {
+		t.Errorf("Get(products) = %+v, want %+v", got, entry)
+	}
+
+	entry.ExpiresAt = now.Add(time.Minute)
+	if err := s.Set("cycles", entry); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	cmd := f.lastCommand("SET")
+	if len(cmd) != 5 || cmd[3] != "PX" {
+		t.Fatalf("SET with expiry sent %q, want a PX argument", cmd)
+	}
+	if ms, _ := strconv.Atoi(cmd[4]); ms <= 0 || ms > 60000 {
+		t.Errorf("PX = %s, want at most a minute", cmd[4])
+	}
+
+	entry.ExpiresAt = now.Add(-time.Minute)
+	if err := s.Set("expired", entry); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, ok, _ := s.Get("expired"); ok {
+		t.Error("an already expired entry was stored")
+	}
+
+	if found, err := s.Delete("products"); err != nil || !found {
+		t.Errorf("Delete(products) = %v, %v; want true, nil", found, err)
+	}
+	if found, err := s.Delete("products"); err != nil || found {
+		t.Errorf("second Delete(products) = %v, %v; want false, nil", found, err)
+	}
+}
+
+func TestRedisStoreListAndClear(t *testing.T) {
+	f := newFakeRedis(t)
+	s := openFakeRedis(t, f, RedisConfig{Prefix: "team*a:"})
+
+	f.mu.Lock()
+	f.data["other:key"] = []byte("not ours")
+	f.mu.Unlock()
+	for _, key := range []string{"e", "d", "c", "b", "a"} {
+		if err := s.Set(key, Entry{Body: []byte(key)}); err != nil {
+			t.Fatalf("Set(%s): %v", key, err)
+		}
+	}
+	if cmd := f.lastCommand("SET"); cmd[1] != "team*a:a" {
+		t.Errorf("SET key = %q, want the configured prefix", cmd[1])
+	}
+
+	infos, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var keys []string
+	for _, info := range infos {
+		keys = append(keys, info.Key)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("List() keys = %q, want %q", keys, want)
+	}
+	if cmd := f.lastCommand("SCAN"); cmd[3] != `team\*a:*` {
+		t.Errorf("SCAN pattern = %q, want the escaped prefix", cmd[3])
+	}
+
+	removed, err := s.Clear()
+	if err != nil || removed != 5 {
+		t.Errorf("Clear() = %d, %v; want 5, nil", removed, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.data) != 1 || f.data["other:key"] == nil {
+		t.Errorf("Clear() left %v, want only the key outside the prefix", f.data)
+	}
+}
+
+func TestRedisStoreErrorReply(t *testing.T) {
+	f := newFakeRedis(t)
+	s := openFakeRedis(t, f, RedisConfig{})
+	f.mu.Lock()
+	f.failKeys[DefaultRedisPrefix+"boom"] = "ERR out of memory"
+	f.mu.Unlock()
+
+	_, _, err := s.Get("boom")
+	var replyErr redisError
+	if !errors.As(err, &replyErr) || string(replyErr) != "ERR out of memory" {
+		t.Fatalf("Get(boom) error = %v, want the error reply", err)
+	}
+	// An error reply leaves the connection usable.
+	if err := s.Set("ok", Entry{Body: []byte("ok")}); err != nil {
+		t.Errorf("Set after error reply: %v", err)
+	}
+}
+
+func TestRedisStoreAuthAndSelect(t *testing.T) {
+	f := newFakeRedis(t)
+	f.password = "s3cret"
+
+	if _, err := OpenRedis(RedisConfig{Addr: f.addr(), Password: "wrong"}); err == nil {
+		t.Error("OpenRedis with a wrong password succeeded")
+	}
+
+	s := openFakeRedis(t, f, RedisConfig{Username: "eolctl", Password: "s3cret", DB: 2})
+	if cmd := f.lastCommand("AUTH"); !reflect.DeepEqual(cmd, []string{"AUTH", "eolctl", "s3cret"}) {
+		t.Errorf("AUTH sent %q", cmd)
+	}
+	if cmd := f.lastCommand("SELECT"); !reflect.DeepEqual(cmd, []string{"SELECT", "2"}) {
+		t.Errorf("SELECT sent %q", cmd)
+	}
+	if err := s.Set("k", Entry{}); err != nil {
+		t.Errorf("Set after AUTH: %v", err)
+	}
+}
+
+func TestRedisStoreReconnects(t *testing.T) {
+	f := newFakeRedis(t)
+	s := openFakeRedis(t, f, RedisConfig{})
+	if err := s.Set("k", Entry{Body: []byte("v")}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	f.mu.Lock()
+	f.dropNext = true
+	f.mu.Unlock()
+	if _, ok, err := s.Get("k"); err != nil || !ok {
+		t.Errorf("Get after a dropped connection = %v, %v; want a hit after reconnecting", ok, err)
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    interface{}
+		wantErr string
+	}{
+		{name: "simple string", input: "+OK\r\n", want: "OK"},
+		{name: "integer", input: ":42\r\n", want: int64(42)},
+		{name: "bulk", input: "$5\r\nhello\r\n", want: []byte("hello")},
+		{name: "empty bulk", input: "$0\r\n\r\n", want: []byte{}},
+		{name: "nil bulk", input: "$-1\r\n", want: nil},
+		{name: "nil array", input: "*-1\r\n", want: nil},
+		{name: "array", input: "*2\r\n$1\r\na\r\n:1\r\n", want: []interface{}{[]byte("a"), int64(1)}},
+		{name: "error", input: "-ERR wrong type\r\n", wantErr: "redis: ERR wrong type"},
+		{name: "invalid bulk length", input: "$x\r\n", wantErr: "invalid bulk length"},
+		{name: "unknown type", input: "?\r\n", wantErr: "unexpected reply"},
+		{name: "empty", input: "\r\n", wantErr: "empty reply"},
+		{name: "truncated bulk", input: "$5\r\nhel", wantErr: "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readReply(bufio.NewReader(strings.NewReader(tt.input)))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readReply(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readReply(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readReply(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Store is a cache backend. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the entry stored under key, ignoring expired entries.
+	Get(key string) (Entry, bool, error)
+	// Set stores entry under key until entry.ExpiresAt.
+	Set(key string, entry Entry) error
+	// Delete removes key and reports whether it was present.
+	Delete(key string) (bool, error)
+	// List returns every live entry, sorted by key.
+	List() ([]Info, error)
+	// Clear removes every entry and returns how many were removed.
+	Clear() (int, error)
+	// Location describes where the data lives, e.g. a file path or address.
+	Location() string
+	// Close persists pending changes and releases the backend.
+	Close() error
+}
+
+// Backend names accepted in Config.
+const (
+	BackendFile  = "file"
+	BackendBolt  = "bolt"
+	BackendRedis = "redis"
+)
+
+// Config selects and configures a cache backend.
+type Config struct {
+	Backend string      `mapstructure:"backend"`
+	Path    string      `mapstructure:"path"`
+	Redis   RedisConfig `mapstructure:"redis"`
+	Logger  *log.Logger `mapstructure:"-"`
+}
+
+// Open returns the backend described by cfg. An empty backend selects the
+// gob file store.
+func Open(cfg Config) (Store, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.StandardLogger()
+	}
+
+	switch cfg.Backend {
+	case "", BackendFile:
+		path := cfg.Path
+		if path == "" {
+			dir, err := Dir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(dir, "cache.gob")
+		}
+		store, err := OpenFile(path, logger)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case BackendBolt:
+		path := cfg.Path
+		if path == "" {
+			dir, err := Dir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(dir, "cache.db")
+		}
+		store, err := OpenBolt(path)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case BackendRedis:
+		store, err := OpenRedis(cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q (expected %s, %s or %s)", cfg.Backend, BackendFile, BackendBolt, BackendRedis)
+	}
+}
+
+// Dir returns the directory local cache backends write to:
+// $XDG_CACHE_HOME/eolctl when XDG_CACHE_HOME is set, ~/.eolctl otherwise.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "eolctl"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".eolctl"), nil
+}
+
+// PurgeStale removes every entry past its freshness window, including
+// entries still within the stale-while-revalidate window, and returns how
+// many were removed. Entries past ExpiresAt are dropped by the stores
+// themselves.
+func PurgeStale(s Store) (int, error) {
+	infos, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	removed := 0
+	for _, info := range infos {
+		if !info.Stale(now) {
+			continue
+		}
+		ok, err := s.Delete(info.Key)
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed++
+		}
+	}
+	return removed, nil
+}
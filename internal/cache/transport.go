@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Mode controls how a Transport uses the cache.
+type Mode int
+
+const (
+	// ModeDefault serves fresh entries from the cache and revalidates stale
+	// ones in the background while serving the stale copy.
+	ModeDefault Mode = iota
+	// ModeRefresh always fetches from the network and stores the result.
+	ModeRefresh
+	// ModeDisabled bypasses the cache entirely.
+	ModeDisabled
+)
+
+var pending sync.WaitGroup
+
+// TTLFunc returns how long a response to req stays fresh. Returning zero
+// disables caching for that request.
+type TTLFunc func(req *http.Request) time.Duration
+
+// Transport is an http.RoundTripper that caches successful and not-found GET
+// responses in the local cache. Stale entries that carry an ETag or
+// Last-Modified validator are revalidated with a conditional request, so an
+// unchanged response costs a 304 instead of a full download.
+type Transport struct {
+	Base  http.RoundTripper
+	Store Store
+	Mode  Mode
+	TTL   TTLFunc
+	// StaleFor is how long after going stale an entry may still be served
+	// while it is revalidated in the background.
+	StaleFor time.Duration
+	// RevalidateTimeout bounds background revalidations, which outlive the
+	// request that started them; zero leaves them to the base transport's
+	// own timeouts.
+	RevalidateTimeout time.Duration
+	// Logf, if set, receives debug messages about cache hits and misses.
+	Logf func(format string, args ...interface{})
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ttl := time.Duration(0)
+	if t.TTL != nil {
+		ttl = t.TTL(req)
+	}
+
+	if t.Mode == ModeDisabled || t.Store == nil || req.Method != http.MethodGet || ttl <= 0 {
+		return t.Base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+
+	entry, found, err := t.Store.Get(key)
+	if err != nil {
+		t.logf("Cache lookup for %s failed: %v", key, err)
+	}
+
+	if t.Mode == ModeDefault {
+		if found {
+			if entry.Fresh(time.Now()) {
+				t.logf("Cache hit for %s", key)
+				return entry.response(req), nil
+			}
+
+			t.logf("Serving stale cache entry for %s while revalidating", key)
+			t.revalidate(req, key, ttl, entry)
+			return entry.response(req), nil
+		}
+		t.logf("Cache miss for %s", key)
+	}
+
+	// A forced refresh still sends the validators: a 304 proves the cached
+	// copy is current just as well as a full response would.
+	return t.fetch(req, key, ttl, entry, found)
+}
+
+// fetch requests key from the network, conditionally when a cached entry
+// with validators is available, and stores the result.
+func (t *Transport) fetch(req *http.Request, key string, ttl time.Duration, entry Entry, found bool) (*http.Response, error) {
+	conditional := found && (entry.ETag != "" || entry.LastModified != "")
+	if conditional {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		t.logf("Cache entry for %s is still valid (304 Not Modified)", key)
+		t.renew(key, ttl, entry, resp.Header)
+		return entry.response(req), nil
+	}
+
+	return t.store(key, ttl, resp)
+}
+
+// revalidate refreshes key in the background. Callers must wait for pending
+// revalidations with Wait before the process exits.
+func (t *Transport) revalidate(req *http.Request, key string, ttl time.Duration, entry Entry) {
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+
+		ctx := context.WithoutCancel(req.Context())
+		if t.RevalidateTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, t.RevalidateTimeout)
+			defer cancel()
+		}
+
+		resp, err := t.fetch(req.Clone(ctx), key, ttl, entry, true)
+		if err != nil {
+			t.logf("Background revalidation of %s failed: %v", key, err)
+			return
+		}
+		resp.Body.Close()
+	}()
+}
+
+// store caches resp when cacheable and returns an equivalent response whose
+// body can still be read by the caller.
+func (t *Transport) store(key string, ttl time.Duration, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	now := time.Now()
+	err = t.Store.Set(key, Entry{
+		StatusCode:   resp.StatusCode,
+		Body:         body,
+		StoredAt:     now,
+		FreshUntil:   now.Add(ttl),
+		ExpiresAt:    now.Add(ttl + t.StaleFor),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+	if err != nil {
+		t.logf("Failed to cache %s: %v", key, err)
+	}
+
+	return resp, nil
+}
+
+// renew extends the freshness of an entry the server confirmed unchanged,
+// picking up any validators the 304 response replaced.
+func (t *Transport) renew(key string, ttl time.Duration, entry Entry, header http.Header) {
+	now := time.Now()
+	entry.StoredAt = now
+	entry.FreshUntil = now.Add(ttl)
+	entry.ExpiresAt = now.Add(ttl + t.StaleFor)
+	if etag := header.Get("ETag"); etag != "" {
+		entry.ETag = etag
+	}
+	if lm := header.Get("Last-Modified"); lm != "" {
+		entry.LastModified = lm
+	}
+	if err := t.Store.Set(key, entry); err != nil {
+		t.logf("Failed to cache %s: %v", key, err)
+	}
+}
+
+func (t *Transport) logf(format string, args ...interface{}) {
+	if t.Logf != nil {
+		t.Logf(format, args...)
+	}
+}
+
+func (e Entry) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if e.ETag != "" {
+		header.Set("ETag", e.ETag)
+	}
+	if e.LastModified != "" {
+		header.Set("Last-Modified", e.LastModified)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// Wait blocks until every background revalidation has finished, so the
+// refreshed entries are in the store before it is closed.
+func Wait() {
+	pending.Wait()
+}
@@ -0,0 +1,301 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// origin serves fixed bodies with an ETag, answers matching conditional
+// requests with 304 and counts the requests it receives per path. Paths
+// without a body are not found, except /error.json which fails.
+type origin struct {
+	mu          sync.Mutex
+	bodies      map[string]string
+	hits        map[string]int
+	conditional map[string]int
+	block       chan struct{}
+}
+
+func newOrigin(t *testing.T, bodies map[string]string) (*origin, *httptest.Server) {
+	t.Helper()
+	o := &origin{bodies: bodies, hits: map[string]int{}, conditional: map[string]int{}}
+	server := httptest.NewServer(o)
+	t.Cleanup(server.Close)
+	return o, server
+}
+
+func (o *origin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	o.hits[r.URL.Path]++
+	body, ok := o.bodies[r.URL.Path]
+	block := o.block
+	if r.Header.Get("If-None-Match") != "" {
+		o.conditional[r.URL.Path]++
+	}
+	o.mu.Unlock()
+
+	if block != nil {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if r.URL.Path == "/error.json" {
+		http.Error(w, "boom", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	etag := `"` + body + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	io.WriteString(w, body)
+}
+
+func (o *origin) set(path, body string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.bodies[path] = body
+}
+
+func (o *origin) counts(path string) (hits, conditional int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.hits[path], o.conditional[path]
+}
+
+func newCachingClient(t *testing.T, mode Mode, ttl TTLFunc) (*http.Client, *FileStore) {
+	t.Helper()
+	store := openFileStore(t, filepath.Join(t.TempDir(), "cache.gob"))
+	if ttl == nil {
+		ttl = func(*http.Request) time.Duration { return time.Hour }
+	}
+	return &http.Client{Transport: &Transport{
+		Base:     http.DefaultTransport,
+		Store:    store,
+		Mode:     mode,
+		TTL:      ttl,
+		StaleFor: time.Hour,
+	}}, store
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading %s: %v", url, err)
+	}
+	return resp.StatusCode, resp.Status, string(body)
+}
+
+// stale marks the cached entry for url as past its freshness lifetime but
+// still within the stale-while-revalidate window.
+func stale(t *testing.T, store Store, url string) {
+	t.Helper()
+	e, ok, err := store.Get(url)
+	if err != nil || !ok {
+		t.Fatalf("no cached entry for %s", url)
+	}
+	e.FreshUntil = time.Now().Add(-time.Minute)
+	if err := store.Set(url, e); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransportCaching(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		// requests is how many GETs are made; wantHits is how many reach
+		// the origin.
+		requests   int
+		wantHits   int
+		wantStatus int
+		mode       Mode
+	}{
+		{"fresh entry served from cache", "/go.json", 3, 1, http.StatusOK, ModeDefault},
+		{"404 cached", "/missing.json", 3, 1, http.StatusNotFound, ModeDefault},
+		{"server error not cached", "/error.json", 2, 2, http.StatusInternalServerError, ModeDefault},
+		{"refresh always fetches", "/go.json", 3, 3, http.StatusOK, ModeRefresh},
+		{"disabled bypasses the cache", "/go.json", 2, 2, http.StatusOK, ModeDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, server := newOrigin(t, map[string]string{"/go.json": "go"})
+			client, store := newCachingClient(t, tt.mode, nil)
+
+			for i := 0; i < tt.requests; i++ {
+				code, status, _ := get(t, client, server.URL+tt.path)
+				want := http.StatusText(tt.wantStatus)
+				if code != tt.wantStatus || status[4:] != want {
+					t.Errorf("request %d: status %q, want %d %s", i+1, status, tt.wantStatus, want)
+				}
+			}
+			if hits, _ := o.counts(tt.path); hits != tt.wantHits {
+				t.Errorf("origin hit %d time(s), want %d", hits, tt.wantHits)
+			}
+
+			_, cached, _ := store.Get(server.URL + tt.path)
+			if wantCached := tt.mode != ModeDisabled && tt.wantStatus != http.StatusInternalServerError; cached != wantCached {
+				t.Errorf("entry cached = %v, want %v", cached, wantCached)
+			}
+		})
+	}
+}
+
+func TestTransportCachedStatusLine(t *testing.T) {
+	_, server := newOrigin(t, map[string]string{"/go.json": "go"})
+	client, _ := newCachingClient(t, ModeDefault, nil)
+
+	get(t, client, server.URL+"/go.json")
+	if _, status, body := get(t, client, server.URL+"/go.json"); status != "200 OK" || body != "go" {
+		t.Errorf("cached response = %q, %q; want 200 OK, go", status, body)
+	}
+	get(t, client, server.URL+"/missing.json")
+	if _, status, _ := get(t, client, server.URL+"/missing.json"); status != "404 Not Found" {
+		t.Errorf("cached 404 status = %q, want 404 Not Found", status)
+	}
+}
+
+func TestTransportStaleWhileRevalidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		newBody  string
+		wantBody string
+	}{
+		{"unchanged, refreshed by a 304", "v1", "v1"},
+		{"changed, replaced by the new body", "v2", "v2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, server := newOrigin(t, map[string]string{"/go.json": "v1"})
+			client, store := newCachingClient(t, ModeDefault, nil)
+			url := server.URL + "/go.json"
+
+			get(t, client, url)
+			stale(t, store, url)
+			o.set("/go.json", tt.newBody)
+
+			if _, _, body := get(t, client, url); body != "v1" {
+				t.Errorf("stale request body = %q, want the stale copy", body)
+			}
+			Wait()
+
+			if hits, conditional := o.counts("/go.json"); hits != 2 || conditional != 1 {
+				t.Errorf("origin hits = %d (%d conditional), want 2 (1 conditional)", hits, conditional)
+			}
+			e, _, _ := store.Get(url)
+			if !e.Fresh(time.Now()) || string(e.Body) != tt.wantBody {
+				t.Errorf("entry after revalidation = %q, fresh %v; want %q, fresh", e.Body, e.Fresh(time.Now()), tt.wantBody)
+			}
+			if _, _, body := get(t, client, url); body != tt.wantBody {
+				t.Errorf("body after revalidation = %q, want %q", body, tt.wantBody)
+			}
+			if hits, _ := o.counts("/go.json"); hits != 2 {
+				t.Errorf("origin hits = %d, want the revalidated entry served from cache", hits)
+			}
+		})
+	}
+}
+
+func TestTransportRefreshRevalidates(t *testing.T) {
+	o, server := newOrigin(t, map[string]string{"/go.json": "v1"})
+	client, store := newCachingClient(t, ModeDefault, nil)
+	url := server.URL + "/go.json"
+	get(t, client, url)
+	stale(t, store, url)
+
+	client.Transport.(*Transport).Mode = ModeRefresh
+	code, _, body := get(t, client, url)
+	if code != http.StatusOK || body != "v1" {
+		t.Errorf("refresh = %d %q, want the cached body after a 304", code, body)
+	}
+	if hits, conditional := o.counts("/go.json"); hits != 2 || conditional != 1 {
+		t.Errorf("origin hits = %d (%d conditional), want 2 (1 conditional)", hits, conditional)
+	}
+	if e, _, _ := store.Get(url); !e.Fresh(time.Now()) {
+		t.Error("refresh did not renew the entry")
+	}
+}
+
+func TestTransportPerEndpointTTL(t *testing.T) {
+	o, server := newOrigin(t, map[string]string{"/all.json": "all", "/go.json": "go", "/live.json": "live"})
+	ttls := map[string]time.Duration{"/all.json": 24 * time.Hour, "/go.json": time.Hour}
+	client, store := newCachingClient(t, ModeDefault, func(req *http.Request) time.Duration {
+		return ttls[req.URL.Path]
+	})
+
+	for _, path := range []string{"/all.json", "/go.json", "/live.json"} {
+		get(t, client, server.URL+path)
+		get(t, client, server.URL+path)
+	}
+
+	for path, ttl := range ttls {
+		e, ok, _ := store.Get(server.URL + path)
+		if !ok {
+			t.Errorf("%s not cached", path)
+			continue
+		}
+		if got := e.FreshUntil.Sub(e.StoredAt); got != ttl {
+			t.Errorf("%s fresh for %s, want %s", path, got, ttl)
+		}
+		if got := e.ExpiresAt.Sub(e.FreshUntil); got != time.Hour {
+			t.Errorf("%s served stale for %s, want the 1h StaleFor", path, got)
+		}
+	}
+	if _, ok, _ := store.Get(server.URL + "/live.json"); ok {
+		t.Error("/live.json cached with a zero TTL")
+	}
+	if hits, _ := o.counts("/live.json"); hits != 2 {
+		t.Errorf("/live.json hit the origin %d time(s), want every request", hits)
+	}
+}
+
+func TestTransportRevalidateTimeout(t *testing.T) {
+	o, server := newOrigin(t, map[string]string{"/go.json": "v1"})
+	client, store := newCachingClient(t, ModeDefault, nil)
+	client.Transport.(*Transport).RevalidateTimeout = 50 * time.Millisecond
+	url := server.URL + "/go.json"
+
+	get(t, client, url)
+	stale(t, store, url)
+
+	o.mu.Lock()
+	o.block = make(chan struct{})
+	o.mu.Unlock()
+	defer close(o.block)
+
+	if _, _, body := get(t, client, url); body != "v1" {
+		t.Errorf("body = %q, want the stale copy", body)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background revalidation ignored RevalidateTimeout")
+	}
+	if e, _, _ := store.Get(url); e.Fresh(time.Now()) {
+		t.Error("timed out revalidation renewed the entry")
+	}
+}
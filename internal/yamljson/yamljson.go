@@ -0,0 +1,198 @@
+// Package yamljson lets configuration files be written in YAML or JSON while
+// being decoded with the encoding/json rules (and custom UnmarshalJSON
+// methods) of the target types, and renders JSON-encoded values as YAML so
+// both formats share field names.
+package yamljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToJSON converts a YAML or JSON document to JSON. Scalars keep their
+// literal text, so an unquoted cycle such as 3.10 stays "3.10" rather than
+// becoming the float 3.1, and dates stay in the form they were written.
+func ToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return []byte("null"), nil
+	}
+
+	v, err := convert(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes a YAML or JSON document into v using encoding/json.
+func Unmarshal(data []byte, v interface{}) error {
+	js, err := ToJSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, v)
+}
+
+func convert(n *yaml.Node) (interface{}, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return convert(n.Content[0])
+	case yaml.AliasNode:
+		return convert(n.Alias)
+	case yaml.SequenceNode:
+		out := make([]interface{}, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := convert(c)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case yaml.MappingNode:
+		out := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			if k.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: mapping keys must be scalars", k.Line)
+			}
+			if k.Tag == "!!merge" {
+				if err := merge(out, n.Content[i+1]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			v, err := convert(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			out[k.Value] = v
+		}
+		return out, nil
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var b bool
+			if err := n.Decode(&b); err != nil {
+				return nil, err
+			}
+			return b, nil
+		case "!!int", "!!float":
+			// json.Number keeps the literal; fall back to a string for
+			// YAML-only spellings such as 0x1F or .inf.
+			if json.Valid([]byte(n.Value)) {
+				return json.Number(n.Value), nil
+			}
+			return n.Value, nil
+		default:
+			return n.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", n.Line)
+}
+
+// merge adds the keys of a merge key's value, a mapping or a sequence of
+// mappings, that out does not have yet. Earlier mappings in a sequence take
+// precedence over later ones.
+func merge(out map[string]interface{}, n *yaml.Node) error {
+	base, err := convert(n)
+	if err != nil {
+		return err
+	}
+	maps, ok := base.([]interface{})
+	if !ok {
+		maps = []interface{}{base}
+	}
+	for _, v := range maps {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("line %d: merge key values must be mappings", n.Line)
+		}
+		for mk, mv := range m {
+			if _, exists := out[mk]; !exists {
+				out[mk] = mv
+			}
+		}
+	}
+	return nil
+}
+
+// FromJSON converts a JSON document to YAML, keeping the order of object
+// keys. Strings that would read back as another type, such as "3.10" or
+// "true", are quoted.
+func FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	n, err := jsonNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{n}})
+}
+
+func jsonNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := jsonNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)}, v)
+			}
+			_, err := dec.Token()
+			return n, err
+		case '[':
+			n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				v, err := jsonNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, v)
+			}
+			_, err := dec.Token()
+			return n, err
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		tag := "!!float"
+		if _, err := t.Int64(); err == nil {
+			tag = "!!int"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", tok)
+}
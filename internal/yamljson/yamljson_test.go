@@ -0,0 +1,161 @@
+package yamljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"decimal keeps its digits", "cycle: 3.10", `{"cycle":3.10}`},
+		{"quoted decimal stays a string", `cycle: "3.10"`, `{"cycle":"3.10"}`},
+		{"booleans", "a: true\nb: false", `{"a":true,"b":false}`},
+		{"quoted boolean stays a string", `a: "true"`, `{"a":"true"}`},
+		{"date stays as written", "eol: 2025-04-30", `{"eol":"2025-04-30"}`},
+		{"null", "a: null\nb: ~\nc:", `{"a":null,"b":null,"c":null}`},
+		{"YAML-only numbers become strings", "a: 0x1F\nb: .inf", `{"a":"0x1F","b":".inf"}`},
+		{"JSON input", `{"levels": ["HIGH", "LOW"], "days": 90}`, `{"days":90,"levels":["HIGH","LOW"]}`},
+		{"empty document", "", `null`},
+		{
+			name: "aliases",
+			yaml: "base: &base {eol: 2025-04-30, lts: true}\ncopy: *base\nlist: [*base]",
+			want: `{"base":{"eol":"2025-04-30","lts":true},"copy":{"eol":"2025-04-30","lts":true},"list":[{"eol":"2025-04-30","lts":true}]}`,
+		},
+		{
+			name: "merge key, explicit keys win",
+			yaml: "defaults: &defaults {days: 90, level: HIGH}\nprod:\n  <<: *defaults\n  level: CRITICAL",
+			want: `{"defaults":{"days":90,"level":"HIGH"},"prod":{"days":90,"level":"CRITICAL"}}`,
+		},
+		{
+			name: "explicit keys before the merge key win too",
+			yaml: "defaults: &defaults {days: 90, level: HIGH}\nprod:\n  level: CRITICAL\n  <<: *defaults",
+			want: `{"defaults":{"days":90,"level":"HIGH"},"prod":{"days":90,"level":"CRITICAL"}}`,
+		},
+		{
+			name: "merge of several maps, earlier maps win",
+			yaml: "a: &a {x: 1, y: 1}\nb: &b {y: 2, z: 2}\nc:\n  <<: [*a, *b]",
+			want: `{"a":{"x":1,"y":1},"b":{"y":2,"z":2},"c":{"x":1,"y":1,"z":2}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("ToJSON: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ToJSON(%q) = %s, want %s", tt.yaml, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJSONErrors(t *testing.T) {
+	for _, data := range []string{"a: [1, 2", "? [a, b]\n: c", "a: *missing", "a:\n  <<: 1", "a:\n  <<: [{x: 1}, 2]"} {
+		if _, err := ToJSON([]byte(data)); err == nil {
+			t.Errorf("ToJSON(%q) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var v struct {
+		Cycle  string `json:"cycle"`
+		Latest string `json:"latest"`
+		EOL    string `json:"eol"`
+	}
+	if err := Unmarshal([]byte("cycle: \"3.10\"\nlatest: '3.10.14'\neol: 2026-10-31\n"), &v); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if v.Cycle != "3.10" || v.Latest != "3.10.14" || v.EOL != "2026-10-31" {
+		t.Errorf("Unmarshal = %+v", v)
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "strings that read back as other types are quoted",
+			json: `{"cycle":"3.10","lts":"true","eol":"2025-04-30","none":"null","hex":"0x1F","empty":""}`,
+			want: "cycle: \"3.10\"\nlts: \"true\"\neol: \"2025-04-30\"\nnone: \"null\"\nhex: \"0x1F\"\nempty: \"\"\n",
+		},
+		{
+			name: "plain strings are not quoted",
+			json: `{"product":"nodejs","phase":"security-only"}`,
+			want: "product: nodejs\nphase: security-only\n",
+		},
+		{
+			name: "numbers, booleans and null keep their type",
+			json: `{"cycle":3.10,"days":-12,"lts":true,"eol":null}`,
+			want: "cycle: 3.10\ndays: -12\nlts: true\neol: null\n",
+		},
+		{
+			name: "key order is preserved",
+			json: `{"zulu":1,"alpha":{"yankee":2,"bravo":3},"mike":[{"x":1,"a":2}]}`,
+			want: "zulu: 1\nalpha:\n    yankee: 2\n    bravo: 3\nmike:\n    - x: 1\n      a: 2\n",
+		},
+		{
+			name: "empty collections",
+			json: `{"list":[],"map":{}}`,
+			want: "list: []\nmap: {}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromJSON([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("FromJSON: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("FromJSON(%s) =\n%s\nwant\n%s", tt.json, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFromJSONRoundTrip checks that YAML output reads back as the JSON it
+// was made from.
+func TestFromJSONRoundTrip(t *testing.T) {
+	in := `{"cycle":"3.10","lts":"true","eol":"2025-04-30","days":3.10,"ok":false,"tags":["1.0","yes","null"],"nested":{"b":"1e3","a":null}}`
+	y, err := FromJSON([]byte(in))
+	if err != nil {
+		t.Fatalf("FromJSON: %v", err)
+	}
+	back, err := ToJSON(y)
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+
+	var want, got interface{}
+	decode := func(data []byte, v *interface{}) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	decode([]byte(in), &want)
+	decode(back, &got)
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("round trip = %s, want %s\nyaml:\n%s", gotJSON, wantJSON, y)
+	}
+}
+
+func TestFromJSONErrors(t *testing.T) {
+	for _, data := range []string{"", `{"a":`, `[1,]`} {
+		if _, err := FromJSON([]byte(data)); err == nil {
+			t.Errorf("FromJSON(%q) succeeded, want an error", data)
+		}
+	}
+}
@@ -123,14 +123,14 @@ func DetectHelmEOL(releases []helm.HelmRelease) ([]HelmStackInfo, error) {
 
 	prompt := fmt.Sprintf(
 		"You are given a list of Helm chart releases from a Kubernetes cluster.\n"+
-			"For EVERY release, map the chart to its corresponding product slug on endoflife.date and extract the major.minor version from app_version.\n"+
+			"For EVERY release, map the chart to its corresponding product slug on endoflife.date and copy its app_version.\n"+
 			"Return ONLY a valid JSON array in this exact format, no extra text:\n"+
-			"[{\"release_name\": \"my-nginx\", \"language\": \"nginx\", \"version\": \"1.25\"}]\n"+
+			"[{\"release_name\": \"my-nginx\", \"language\": \"nginx\", \"version\": \"1.25.3\"}]\n"+
 			"Rules:\n"+
 			"- Include ALL releases — do not omit any.\n"+
 			"- Use known endoflife.date slugs when possible (e.g. nginx, kubernetes, redis, postgresql, cert-manager, prometheus, grafana).\n"+
 			"- If unsure, use the chart name (without the version suffix) as the slug.\n"+
-			"- Extract major.minor from app_version (e.g. \"1.25.3\" → \"1.25\").\n\n%s",
+			"- Copy app_version unchanged (e.g. \"1.25.3\"); it is matched to a release cycle afterwards.\n\n%s",
 		string(data),
 	)
 
@@ -14,6 +14,11 @@ import (
 type StackInfo struct {
 	Language string `json:"language"`
 	Version  string `json:"version"`
+	// File is the manifest the version was found in, relative to the
+	// project directory.
+	File string `json:"file,omitempty"`
+	// Line is the line of File declaring the version, 0 when unknown.
+	Line int `json:"line,omitempty"`
 }
 
 func DetectStack(projectDir string) ([]StackInfo, error) {
@@ -23,9 +28,10 @@ func DetectStack(projectDir string) ([]StackInfo, error) {
 		return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable is not set")
 	}
 
-	filesToCheck := []string{"go.mod", "requirements.txt", "Pipfile", "pyproject.toml", "package.json"}
+	filesToCheck := []string{"go.mod", "requirements.txt", "Pipfile", "pyproject.toml", "package.json", "Dockerfile"}
 
 	var sb strings.Builder
+	manifests := map[string][]byte{}
 
 	skipDirs := map[string]bool{
 		"node_modules": true,
@@ -46,12 +52,18 @@ func DetectStack(projectDir string) ([]StackInfo, error) {
 			return nil
 		}
 		for _, f := range filesToCheck {
-			if info.Name() == f {
+			if info.Name() == f || (f == "Dockerfile" && IsDockerfile(info.Name())) {
 				content, err := os.ReadFile(path)
 				if err != nil {
 					return err
 				}
-				fmt.Fprintf(&sb, "--- %s ---\n%s\n", info.Name(), string(content))
+				rel, err := filepath.Rel(projectDir, path)
+				if err != nil {
+					rel = info.Name()
+				}
+				rel = filepath.ToSlash(rel)
+				manifests[rel] = content
+				fmt.Fprintf(&sb, "--- %s ---\n%s\n", rel, string(content))
 				break
 			}
 		}
@@ -63,8 +75,8 @@ func DetectStack(projectDir string) ([]StackInfo, error) {
 	}
 
 	prompt := fmt.Sprintf(
-		"Based on these project files, identify all programming languages and their versions.\n"+
-			"Reply with ONLY a valid JSON array in this exact format, no extra text: [{\"language\": \"go\", \"version\": \"1.23\"}, {\"language\": \"nodejs\", \"version\": \"20\"}]\n\n%s",
+		"Based on these project files, identify all programming languages and their versions, exactly as declared in the files.\n"+
+			"Reply with ONLY a valid JSON array in this exact format, no extra text, where file is the path of the file the version was found in, as given in its --- header ---: [{\"language\": \"go\", \"version\": \"1.23\", \"file\": \"go.mod\"}, {\"language\": \"nodejs\", \"version\": \"20\", \"file\": \"web/package.json\"}]\n\n%s",
 		sb.String(),
 	)
 
@@ -94,6 +106,11 @@ func DetectStack(projectDir string) ([]StackInfo, error) {
 			if err := json.Unmarshal([]byte(text), &result); err != nil {
 				return nil, fmt.Errorf("failed to parse Claude response: %w", err)
 			}
+			for i, s := range result {
+				if content, ok := manifests[s.File]; ok {
+					result[i].Line = LocateVersion(s.File, content, s.Version)
+				}
+			}
 			return result, nil
 		}
 	}
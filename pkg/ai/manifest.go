@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"path"
+	"strings"
+)
+
+// versionKeys are the declarations that pin the runtime version in each
+// manifest, in order of preference.
+var versionKeys = map[string][]string{
+	"go.mod":         {"go ", "toolchain "},
+	"pyproject.toml": {"requires-python", "python =", "python="},
+	"Pipfile":        {"python_full_version", "python_version"},
+}
+
+// IsDockerfile reports whether name is a Dockerfile: Dockerfile,
+// Containerfile, Dockerfile.<suffix> or <prefix>.Dockerfile.
+func IsDockerfile(name string) bool {
+	return name == "Dockerfile" || name == "Containerfile" ||
+		strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile")
+}
+
+// LocateVersion returns the 1-based line of a manifest that declares the
+// runtime version: the go directive in go.mod, engines.node in
+// package.json, requires-python in pyproject.toml, the FROM instruction of
+// a Dockerfile and so on. Otherwise it returns the first line mentioning
+// version, or 0 if there is none.
+func LocateVersion(file string, content []byte, version string) int {
+	lines := strings.Split(string(content), "\n")
+	name := path.Base(file)
+
+	if IsDockerfile(name) {
+		return locateBaseImage(lines, version)
+	}
+
+	if name == "package.json" {
+		if engines := findLine(lines, 0, `"engines"`); engines > 0 {
+			if node := findLine(lines, engines-1, `"node"`); node > 0 {
+				return node
+			}
+			return engines
+		}
+	}
+	for _, key := range versionKeys[name] {
+		if n := findLine(lines, 0, key); n > 0 {
+			return n
+		}
+	}
+	if version == "" {
+		return 0
+	}
+	for i, line := range lines {
+		if strings.Contains(line, version) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// locateBaseImage returns the FROM line of a Dockerfile whose image tag
+// mentions version, else the first line mentioning it, such as an ARG
+// used in the tag, else the FROM line of the final stage.
+func locateBaseImage(lines []string, version string) int {
+	last := 0
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		last = i + 1
+		if version != "" && strings.Contains(imageTag(fields[1:]), version) {
+			return i + 1
+		}
+	}
+	if version != "" {
+		for i, line := range lines {
+			if strings.Contains(line, version) {
+				return i + 1
+			}
+		}
+	}
+	return last
+}
+
+// imageTag returns the tag of the image in the arguments of a FROM
+// instruction, skipping flags such as --platform.
+func imageTag(args []string) string {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			continue
+		}
+		image, _, _ := strings.Cut(arg, "@")
+		if i := strings.LastIndexByte(image, ':'); i > strings.LastIndexByte(image, '/') {
+			return image[i+1:]
+		}
+		return ""
+	}
+	return ""
+}
+
+// findLine returns the 1-based number of the first line from index start
+// that starts with prefix once indentation is trimmed, or 0.
+func findLine(lines []string, start int, prefix string) int {
+	for i := start; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), prefix) {
+			return i + 1
+		}
+	}
+	return 0
+}
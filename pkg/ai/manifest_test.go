@@ -0,0 +1,88 @@
+package ai
+
+import "testing"
+
+const goMod = `module example.com/api
+
+go 1.22.5
+
+toolchain go1.23.1
+
+require github.com/spf13/cobra v1.8.1
+`
+
+const packageJSON = `{
+  "name": "web",
+  "version": "20.1.0",
+  "dependencies": {
+    "node-fetch": "^3.3.2"
+  },
+  "engines": {
+    "npm": ">=10",
+    "node": ">=20.11"
+  }
+}
+`
+
+const dockerfile = `# syntax=docker/dockerfile:1
+ARG PYTHON_VERSION=3.12
+FROM --platform=$BUILDPLATFORM golang:1.22-alpine AS build
+WORKDIR /src
+RUN go build -o /app ./...
+
+FROM python:${PYTHON_VERSION}-slim
+COPY --from=build /app /usr/local/bin/app
+`
+
+func TestLocateVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		version string
+		want    int
+	}{
+		{"go directive", "go.mod", goMod, "1.22.5", 3},
+		{"go directive before toolchain", "services/api/go.mod", goMod, "1.23", 3},
+		{"go.mod without a go directive", "go.mod", "module x\n\ntoolchain go1.23.1\n", "1.23", 3},
+		{"engines.node", "package.json", packageJSON, "20", 9},
+		{"engines.node in a nested package", "web/package.json", packageJSON, ">=20.11", 9},
+		{"package.json without engines falls back to the version", "package.json", "{\n  \"name\": \"web\",\n  \"volta\": {\"node\": \"18.19.0\"}\n}\n", "18.19.0", 3},
+		{"requires-python", "pyproject.toml", "[project]\nname = \"api\"\nrequires-python = \">=3.11\"\n", "3.11", 3},
+		{"Pipfile", "Pipfile", "[packages]\nflask = \"*\"\n\n[requires]\npython_version = \"3.12\"\n", "3.12", 5},
+		{"Dockerfile FROM with the version", "Dockerfile", dockerfile, "1.22", 3},
+		{"Dockerfile ARG used in the tag", "Dockerfile", dockerfile, "3.12", 2},
+		{"Dockerfile final stage without a version", "Dockerfile", dockerfile, "", 7},
+		{"Dockerfile FROM preferred over an earlier mention", "build/Dockerfile", "RUN echo 20\nFROM node:20\n", "20", 2},
+		{"Containerfile", "Containerfile", "FROM registry.example.com:5000/python:3.11@sha256:abc\n", "3.11", 1},
+		{"suffixed Dockerfile", "Dockerfile.prod", dockerfile, "1.22", 3},
+		{"prefixed Dockerfile", "api.Dockerfile", dockerfile, "1.22", 3},
+		{"registry port is not a tag", "Dockerfile", "# mirror on port 5000\nFROM registry:5000/golang\n", "5000", 1},
+		{"other files fall back to the version", "requirements.txt", "flask==3.0.0\n# python 3.12\n", "3.12", 2},
+		{"version not found", "requirements.txt", "flask==3.0.0\n", "3.12", 0},
+		{"no version", "requirements.txt", "flask==3.0.0\n", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LocateVersion(tt.file, []byte(tt.content), tt.version); got != tt.want {
+				t.Errorf("LocateVersion(%s, %q) = %d, want %d", tt.file, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDockerfile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Dockerfile":      true,
+		"Containerfile":   true,
+		"Dockerfile.prod": true,
+		"api.Dockerfile":  true,
+		"dockerfile":      false,
+		"Dockerfiles":     false,
+		"go.mod":          false,
+	} {
+		if got := IsDockerfile(name); got != want {
+			t.Errorf("IsDockerfile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package artifacthub
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,9 +10,47 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/policy"
 )
 
-var httpClient = &http.Client{Timeout: 15 * time.Second}
+// DefaultBaseURL is the public ArtifactHub API.
+const DefaultBaseURL = "https://artifacthub.io/api/v1"
+
+// Client queries the ArtifactHub API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at a different API root, e.g. a mirror.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient returns a Client for the public API unless overridden by opts.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
 
 type Package struct {
 	Name       string     `json:"name"`
@@ -32,19 +71,19 @@ type searchResponse struct {
 
 // SearchPackage queries ArtifactHub for the most relevant stable Helm package matching name.
 // It fetches up to 5 candidates and returns the first whose version is not a pre-release.
-func SearchPackage(name string) (*Package, error) {
+func (c *Client) SearchPackage(ctx context.Context, name string) (*Package, error) {
 	endpoint := fmt.Sprintf(
-		"https://artifacthub.io/api/v1/packages/search?kind=0&ts_query_web=%s&limit=5",
-		url.QueryEscape(name),
+		"%s/packages/search?kind=0&ts_query_web=%s&limit=5",
+		c.baseURL, url.QueryEscape(name),
 	)
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := httpClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query ArtifactHub: %w", err)
 	}
@@ -93,48 +132,39 @@ func isPreRelease(version string) bool {
 }
 
 // RiskFromStaleness derives a risk level by comparing the installed app version
-// against the latest version reported by ArtifactHub.
+// against the latest version reported by ArtifactHub, using the built-in policy.
 // Returns a risk label (CRITICAL/HIGH/MEDIUM/LOW/UNKNOWN) and a short EOL-column label.
 func RiskFromStaleness(installed, latest string, deprecated bool) (riskLevel, eolLabel string) {
+	p := policy.Default()
+	return RiskFromStalenessRules(*p.Staleness, p.UnknownLevel, installed, latest, deprecated)
+}
+
+// RiskFromStalenessRules is RiskFromStaleness with the major/minor distance
+// rules and unknown level name of a risk policy.
+func RiskFromStalenessRules(rules policy.Staleness, unknown, installed, latest string, deprecated bool) (riskLevel, eolLabel string) {
 	if deprecated {
-		return "CRITICAL", "deprecated"
+		return rules.ForStaleness(true, 0, 0), "deprecated"
 	}
 
 	installed = strings.TrimPrefix(strings.TrimSpace(installed), "v")
 	latest = strings.TrimPrefix(strings.TrimSpace(latest), "v")
 
 	if installed == "" || latest == "" {
-		return "UNKNOWN", "unknown"
+		return unknown, "unknown"
 	}
 
+	label := fmt.Sprintf("latest: %s", latest)
 	if installed == latest {
-		return "LOW", fmt.Sprintf("latest: %s", latest)
+		return rules.Current, label
 	}
 
-	installedMajor := majorOf(installed)
-	latestMajor := majorOf(latest)
-	majorDiff := latestMajor - installedMajor
-
-	if majorDiff >= 2 {
-		return "CRITICAL", fmt.Sprintf("latest: %s", latest)
-	}
-	if majorDiff == 1 {
-		return "HIGH", fmt.Sprintf("latest: %s", latest)
-	}
-
-	// Same major — check minor
-	installedMinor := minorOf(installed)
-	latestMinor := minorOf(latest)
-	minorDiff := latestMinor - installedMinor
-
-	if minorDiff >= 3 {
-		return "HIGH", fmt.Sprintf("latest: %s", latest)
-	}
-	if minorDiff >= 1 {
-		return "MEDIUM", fmt.Sprintf("latest: %s", latest)
+	majorDiff := majorOf(latest) - majorOf(installed)
+	minorDiff := 0
+	if majorDiff == 0 {
+		minorDiff = minorOf(latest) - minorOf(installed)
 	}
 
-	return "LOW", fmt.Sprintf("latest: %s", latest)
+	return rules.ForStaleness(false, majorDiff, minorDiff), label
 }
 
 func majorOf(version string) int {
@@ -0,0 +1,126 @@
+// Package baseline records the findings of a scan so later scans can report
+// only components that are new or whose risk got worse.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// schemaVersion is bumped on incompatible changes to the file format.
+const schemaVersion = 1
+
+// Finding is one component as recorded in a baseline. ID identifies the
+// component across scans independently of its version, e.g. the manifest
+// path and product for a project or namespace/release for a cluster.
+type Finding struct {
+	ID        string `json:"id"`
+	Product   string `json:"product"`
+	Version   string `json:"version"`
+	Risk      string `json:"risk"`
+	PatchRisk string `json:"patch_risk,omitempty"`
+}
+
+// Status is how a finding compares to the baseline.
+type Status string
+
+const (
+	// StatusNew: the component is not in the baseline.
+	StatusNew Status = "new"
+	// StatusWorsened: the EOL or patch risk is more severe than recorded.
+	StatusWorsened Status = "worsened"
+	// StatusKnown: recorded with the same or a less severe risk.
+	StatusKnown Status = "known"
+)
+
+// Baseline is a set of recorded findings.
+type Baseline struct {
+	Schema    int       `json:"schema"`
+	CreatedAt time.Time `json:"created_at"`
+	Findings  []Finding `json:"findings"`
+
+	byID map[string]Finding
+}
+
+// New returns a baseline of findings, sorted by ID for stable diffs.
+func New(findings []Finding, now time.Time) *Baseline {
+	sorted := append([]Finding(nil), findings...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return &Baseline{Schema: schemaVersion, CreatedAt: now.UTC(), Findings: sorted}
+}
+
+// Load reads a baseline file written by Write.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if b.Schema != schemaVersion {
+		return nil, fmt.Errorf("baseline %s has unsupported schema %d", path, b.Schema)
+	}
+	return &b, nil
+}
+
+// Write saves the baseline to path as indented JSON, meant to be committed
+// alongside the code it describes.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create baseline directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Compare reports how f compares to its recorded counterpart. rank orders
+// levels from most severe (0) and returns -1 for levels outside the
+// policy, such as UNKNOWN. A level outside the policy is never worse; going
+// from one to a rated level is, when that level ranks at or above failRank,
+// or at any rank when failRank is negative.
+func (b *Baseline) Compare(f Finding, rank func(level string) int, failRank int) Status {
+	if b.byID == nil {
+		b.byID = make(map[string]Finding, len(b.Findings))
+		for _, r := range b.Findings {
+			b.byID[r.ID] = r
+		}
+	}
+
+	recorded, ok := b.byID[f.ID]
+	if !ok {
+		return StatusNew
+	}
+	if worse(f.Risk, recorded.Risk, rank, failRank) || worse(f.PatchRisk, recorded.PatchRisk, rank, failRank) {
+		return StatusWorsened
+	}
+	return StatusKnown
+}
+
+// worse reports whether level is more severe than recorded. A level that
+// was unknown before is worse once rated at or above failRank, so a
+// component whose EOL date was only just announced cannot slip past a gate.
+func worse(level, recorded string, rank func(string) int, failRank int) bool {
+	now, was := rank(level), rank(recorded)
+	switch {
+	case now < 0:
+		return false
+	case was < 0:
+		return failRank < 0 || now <= failRank
+	default:
+		return now < was
+	}
+}
@@ -0,0 +1,90 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// rank orders the built-in levels; UNKNOWN is outside the policy.
+func rank(level string) int {
+	for i, l := range []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"} {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestCompare(t *testing.T) {
+	b := New([]Finding{
+		{ID: "go.mod/go", Risk: "HIGH", PatchRisk: "MEDIUM"},
+		{ID: "package.json/nodejs", Risk: "UNKNOWN"},
+		{ID: "ingress/nginx", Risk: "LOW", PatchRisk: "UNKNOWN"},
+	}, time.Now())
+
+	tests := []struct {
+		name     string
+		finding  Finding
+		failRank int
+		want     Status
+	}{
+		{"not recorded", Finding{ID: "Pipfile/python", Risk: "LOW"}, -1, StatusNew},
+		{"same risk", Finding{ID: "go.mod/go", Risk: "HIGH", PatchRisk: "MEDIUM"}, -1, StatusKnown},
+		{"less severe", Finding{ID: "go.mod/go", Risk: "LOW", PatchRisk: "LOW"}, -1, StatusKnown},
+		{"risk worsened", Finding{ID: "go.mod/go", Risk: "CRITICAL", PatchRisk: "MEDIUM"}, -1, StatusWorsened},
+		{"patch risk worsened", Finding{ID: "go.mod/go", Risk: "HIGH", PatchRisk: "HIGH"}, -1, StatusWorsened},
+		{"rated to unknown", Finding{ID: "go.mod/go", Risk: "UNKNOWN", PatchRisk: "UNKNOWN"}, -1, StatusKnown},
+		{"unknown stays unknown", Finding{ID: "package.json/nodejs", Risk: "UNKNOWN"}, 1, StatusKnown},
+		{"unknown to rated without gate", Finding{ID: "package.json/nodejs", Risk: "LOW"}, -1, StatusWorsened},
+		{"unknown to fail level", Finding{ID: "package.json/nodejs", Risk: "HIGH"}, 1, StatusWorsened},
+		{"unknown to above fail level", Finding{ID: "package.json/nodejs", Risk: "CRITICAL"}, 1, StatusWorsened},
+		{"unknown to below fail level", Finding{ID: "package.json/nodejs", Risk: "MEDIUM"}, 1, StatusKnown},
+		{"unknown patch risk to fail level", Finding{ID: "ingress/nginx", Risk: "LOW", PatchRisk: "HIGH"}, 1, StatusWorsened},
+		{"unknown patch risk to below fail level", Finding{ID: "ingress/nginx", Risk: "LOW", PatchRisk: "LOW"}, 1, StatusKnown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Compare(tt.finding, rank, tt.failRank); got != tt.want {
+				t.Errorf("Compare(%+v, failRank %d) = %s, want %s", tt.finding, tt.failRank, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "baseline.json")
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := New([]Finding{
+		{ID: "b", Product: "go", Version: "1.22.0", Risk: "HIGH"},
+		{ID: "a", Product: "nodejs", Version: "20.11.1", Risk: "LOW", PatchRisk: "MEDIUM"},
+	}, created)
+	if err := b.Write(path); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !loaded.CreatedAt.Equal(created) || len(loaded.Findings) != 2 {
+		t.Fatalf("Load() = %+v, want the written baseline", loaded)
+	}
+	if loaded.Findings[0].ID != "a" || loaded.Findings[1].ID != "b" {
+		t.Errorf("findings not sorted by ID: %+v", loaded.Findings)
+	}
+	if got := loaded.Compare(Finding{ID: "a", Risk: "LOW", PatchRisk: "MEDIUM"}, rank, -1); got != StatusKnown {
+		t.Errorf("Compare after Load = %s, want %s", got, StatusKnown)
+	}
+}
+
+func TestLoadRejectsOtherSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte(`{"schema": 99, "findings": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load accepted an unsupported schema")
+	}
+}
@@ -0,0 +1,317 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+)
+
+// FormatVersion is the on-disk layout version written by this package.
+// Bundles with a newer version are rejected on read.
+const FormatVersion = 1
+
+const (
+	manifestFile = "manifest.json"
+	productsFile = "all.json"
+	productsDir  = "products"
+)
+
+// Manifest describes a bundle and carries a SHA-256 checksum for every
+// file in the archive.
+type Manifest struct {
+	FormatVersion int               `json:"format_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Source        string            `json:"source"`
+	Products      int               `json:"products"`
+	Checksums     map[string]string `json:"checksums"`
+}
+
+// Bundle is an in-memory snapshot of the endoflife.date product catalog and
+// every product's release cycles. It implements endoflife.Provider so it can
+// stand in for the live API.
+type Bundle struct {
+	Manifest Manifest
+
+	products []string
+	cycles   map[string][]endoflife.Cycle
+}
+
+var _ endoflife.Provider = (*Bundle)(nil)
+
+// Build downloads the full catalog from p. Products the API lists but cannot
+// serve are skipped. progress, if non-nil, is called after each product.
+// Cancelling ctx stops queued fetches and returns the context's error.
+func Build(ctx context.Context, p endoflife.Provider, source string, concurrency int, progress func(done, total int)) (*Bundle, error) {
+	products, err := p.GetAvailableProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product list: %w", err)
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	b := &Bundle{cycles: make(map[string][]endoflife.Cycle, len(products))}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		done     int
+	)
+	sem := make(chan struct{}, concurrency)
+
+loop:
+	for _, product := range products {
+		// Stop queueing fetches once the export is cancelled.
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+		wg.Add(1)
+		go func(product string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			cycles, err := p.GetProduct(ctx, product)
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if progress != nil {
+				progress(done, len(products))
+			}
+			switch {
+			case errors.Is(err, endoflife.ErrNotFound):
+				return
+			case err != nil:
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to fetch %s: %w", product, err)
+				}
+				return
+			}
+			b.cycles[product] = cycles
+		}(product)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	for product := range b.cycles {
+		b.products = append(b.products, product)
+	}
+	sort.Strings(b.products)
+
+	b.Manifest = Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Source:        source,
+		Products:      len(b.products),
+	}
+
+	return b, nil
+}
+
+// Write serialises the bundle as a gzip-compressed tar archive.
+func (b *Bundle) Write(w io.Writer) error {
+	files := make(map[string][]byte, len(b.products)+1)
+
+	data, err := json.Marshal(b.products)
+	if err != nil {
+		return fmt.Errorf("failed to encode product list: %w", err)
+	}
+	files[productsFile] = data
+
+	for _, product := range b.products {
+		data, err := json.Marshal(b.cycles[product])
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", product, err)
+		}
+		files[productPath(product)] = data
+	}
+
+	names := make([]string, 0, len(files))
+	b.Manifest.Checksums = make(map[string]string, len(files))
+	for name, data := range files {
+		names = append(names, name)
+		b.Manifest.Checksums[name] = checksum(data)
+	}
+	sort.Strings(names)
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeFile(tw, manifestFile, manifest, b.Manifest.CreatedAt); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := writeFile(tw, name, files[name], b.Manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// Read parses a bundle archive and verifies every file against the manifest.
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		files[hdr.Name] = data
+	}
+
+	raw, ok := files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("archive has no %s", manifestFile)
+	}
+
+	b := &Bundle{cycles: make(map[string][]endoflife.Cycle)}
+	if err := json.Unmarshal(raw, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if b.Manifest.FormatVersion < 1 || b.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d (this build supports up to %d)", b.Manifest.FormatVersion, FormatVersion)
+	}
+
+	for name, sum := range b.Manifest.Checksums {
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing %s", name)
+		}
+		if checksum(data) != sum {
+			return nil, fmt.Errorf("checksum mismatch for %s", name)
+		}
+	}
+	for name := range files {
+		if _, ok := b.Manifest.Checksums[name]; !ok && name != manifestFile {
+			return nil, fmt.Errorf("unexpected file %s in bundle", name)
+		}
+	}
+
+	if err := json.Unmarshal(files[productsFile], &b.products); err != nil {
+		return nil, fmt.Errorf("failed to parse product list: %w", err)
+	}
+	for _, product := range b.products {
+		var cycles []endoflife.Cycle
+		if err := json.Unmarshal(files[productPath(product)], &cycles); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", product, err)
+		}
+		b.cycles[product] = cycles
+	}
+
+	return b, nil
+}
+
+// Open reads and verifies the bundle at path.
+func Open(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Age returns how long ago the bundle was created.
+func (b *Bundle) Age() time.Duration {
+	return time.Since(b.Manifest.CreatedAt)
+}
+
+func (b *Bundle) GetAvailableProducts(ctx context.Context) ([]string, error) {
+	return append([]string(nil), b.products...), nil
+}
+
+func (b *Bundle) GetProduct(ctx context.Context, product string) ([]endoflife.Cycle, error) {
+	cycles, ok := b.cycles[product]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not in the offline bundle", endoflife.ErrNotFound, product)
+	}
+	return append([]endoflife.Cycle(nil), cycles...), nil
+}
+
+func (b *Bundle) GetCycle(ctx context.Context, product, cycle string) (*endoflife.Cycle, error) {
+	cycles, err := b.GetProduct(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cycles {
+		if cycles[i].Cycle == cycle {
+			return &cycles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s is not in the offline bundle", endoflife.ErrNotFound, product, cycle)
+}
+
+func productPath(product string) string {
+	// Slugs never contain slashes, but guard against a hostile catalog
+	// escaping the products directory.
+	return path.Join(productsDir, strings.ReplaceAll(product, "/", "_")+".json")
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
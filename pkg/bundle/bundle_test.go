@@ -0,0 +1,258 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+)
+
+// apiServer serves a small endoflife.date catalog. "ghost" is listed but
+// not served, as happens when the API lists a product it has removed.
+func apiServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	pages := map[string]string{
+		"/all.json":    `["go", "nodejs", "ghost"]`,
+		"/go.json":     `[{"cycle": "1.23", "eol": false, "latest": "1.23.4"}, {"cycle": "1.22", "eol": "2025-02-11", "latest": "1.22.12"}]`,
+		"/nodejs.json": `[{"cycle": "20", "eol": "2026-04-30", "lts": true, "latest": "20.18.0"}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func buildArchive(t *testing.T) []byte {
+	t.Helper()
+	client := endoflife.NewClient(endoflife.WithBaseURL(apiServer(t).URL))
+
+	var progress atomic.Int32
+	b, err := Build(context.Background(), client, "test", 2, func(done, total int) {
+		progress.Add(1)
+		if total != 3 {
+			t.Errorf("progress total = %d, want 3", total)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if progress.Load() != 3 {
+		t.Errorf("progress called %d times, want 3", progress.Load())
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestBuildWriteRead(t *testing.T) {
+	b, err := Read(bytes.NewReader(buildArchive(t)))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	if b.Manifest.FormatVersion != FormatVersion || b.Manifest.Source != "test" || b.Manifest.Products != 2 {
+		t.Errorf("manifest = %+v", b.Manifest)
+	}
+	if age := b.Age(); age < 0 || age > time.Minute {
+		t.Errorf("Age() = %s, want a freshly built bundle", age)
+	}
+
+	ctx := context.Background()
+	products, _ := b.GetAvailableProducts(ctx)
+	if strings.Join(products, ",") != "go,nodejs" {
+		t.Errorf("products = %q, want go and nodejs without the unserved product", products)
+	}
+
+	cycles, err := b.GetProduct(ctx, "go")
+	if err != nil || len(cycles) != 2 {
+		t.Fatalf("GetProduct(go) = %d cycles, %v", len(cycles), err)
+	}
+	if !cycles[1].EOL.IsDate() || cycles[1].EOL.String() != "2025-02-11" || cycles[1].Latest != "1.22.12" {
+		t.Errorf("go 1.22 = %+v, want the cycle as served", cycles[1])
+	}
+
+	c, err := b.GetCycle(ctx, "nodejs", "20")
+	if err != nil || !c.LTS.IsBool() || !c.LTS.Bool {
+		t.Errorf("GetCycle(nodejs, 20) = %+v, %v", c, err)
+	}
+	if _, err := b.GetCycle(ctx, "nodejs", "16"); !errors.Is(err, endoflife.ErrNotFound) {
+		t.Errorf("GetCycle(nodejs, 16) error = %v, want ErrNotFound", err)
+	}
+	if _, err := b.GetProduct(ctx, "ghost"); !errors.Is(err, endoflife.ErrNotFound) {
+		t.Errorf("GetProduct(ghost) error = %v, want ErrNotFound", err)
+	}
+}
+
+// rewrite returns archive with its files passed through edit; edit may
+// change the data or return nil to drop a file, and extra files are
+// appended.
+func rewrite(t *testing.T, archive []byte, edit func(name string, data []byte) []byte, extra map[string][]byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	write := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		if data = edit(hdr.Name, data); data != nil {
+			write(hdr.Name, data)
+		}
+	}
+	for name, data := range extra {
+		write(name, data)
+	}
+	tw.Close()
+	gw.Close()
+	return out.Bytes()
+}
+
+func TestReadRejectsInvalidBundles(t *testing.T) {
+	archive := buildArchive(t)
+	keep := func(name string, data []byte) []byte { return data }
+
+	tests := []struct {
+		name    string
+		archive []byte
+		wantErr string
+	}{
+		{
+			name: "tampered product",
+			archive: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == "products/go.json" {
+					return bytes.Replace(data, []byte("2025-02-11"), []byte("2099-02-11"), 1)
+				}
+				return data
+			}, nil),
+			wantErr: "checksum mismatch for products/go.json",
+		},
+		{
+			name: "missing file",
+			archive: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == "products/nodejs.json" {
+					return nil
+				}
+				return data
+			}, nil),
+			wantErr: "bundle is missing products/nodejs.json",
+		},
+		{
+			name:    "unexpected file",
+			archive: rewrite(t, archive, keep, map[string][]byte{"products/evil.json": []byte("[]")}),
+			wantErr: "unexpected file products/evil.json",
+		},
+		{
+			name: "newer format version",
+			archive: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == manifestFile {
+					return bytes.Replace(data, []byte(`"format_version": 1`), []byte(`"format_version": 2`), 1)
+				}
+				return data
+			}, nil),
+			wantErr: "unsupported bundle format version 2",
+		},
+		{
+			name: "no manifest",
+			archive: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == manifestFile {
+					return nil
+				}
+				return data
+			}, nil),
+			wantErr: "archive has no manifest.json",
+		},
+		{
+			name:    "not an archive",
+			archive: []byte("PK\x03\x04 not gzip"),
+			wantErr: "not a bundle archive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.archive))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// blockingProvider lists many products and blocks every fetch until the
+// context is cancelled.
+type blockingProvider struct {
+	fetches atomic.Int32
+}
+
+func (p *blockingProvider) GetAvailableProducts(ctx context.Context) ([]string, error) {
+	return []string{"a", "b", "c", "d", "e", "f"}, nil
+}
+
+func (p *blockingProvider) GetProduct(ctx context.Context, product string) ([]endoflife.Cycle, error) {
+	p.fetches.Add(1)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (p *blockingProvider) GetCycle(ctx context.Context, product, cycle string) (*endoflife.Cycle, error) {
+	return nil, endoflife.ErrNotFound
+}
+
+func TestBuildStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	p := &blockingProvider{}
+	done := make(chan error, 1)
+	go func() {
+		_, err := Build(ctx, p, "test", 2, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Build error = %v, want the context error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Build did not return after the context was cancelled")
+	}
+	if n := p.fetches.Load(); n > 4 {
+		t.Errorf("%d fetches started, want queued fetches to stop after cancellation", n)
+	}
+}
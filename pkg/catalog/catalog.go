@@ -0,0 +1,141 @@
+// Package catalog loads locally defined products and cycles, for internal
+// platforms endoflife.date does not list and for local overrides of public
+// products, and layers them over another endoflife.Provider.
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/asafdavid23/eolctl/internal/yamljson"
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+)
+
+// Product is a catalog entry. Cycles use the same shape as the
+// endoflife.date API's cycle objects.
+type Product struct {
+	Name string `json:"name"`
+	// Replace discards the public data for this product instead of merging
+	// the catalog cycles into it.
+	Replace bool              `json:"replace,omitempty"`
+	Cycles  []endoflife.Cycle `json:"cycles"`
+}
+
+// Catalog is a set of locally defined products, keyed by product name.
+type Catalog struct {
+	products map[string]Product
+}
+
+type file struct {
+	Products []Product `json:"products"`
+}
+
+// Load reads and merges the catalog files at paths. Files may be YAML or
+// JSON; a product defined in several files has its cycles merged, with
+// later files taking precedence.
+func Load(paths ...string) (*Catalog, error) {
+	c := &Catalog{products: make(map[string]Product)}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog: %w", err)
+		}
+
+		var f file
+		if err := yamljson.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse catalog %s: %w", path, err)
+		}
+		if err := c.add(f.Products); err != nil {
+			return nil, fmt.Errorf("invalid catalog %s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+func (c *Catalog) add(products []Product) error {
+	for _, p := range products {
+		if p.Name == "" {
+			return fmt.Errorf("product without a name")
+		}
+
+		seen := make(map[string]bool, len(p.Cycles))
+		for _, cycle := range p.Cycles {
+			if cycle.Cycle == "" {
+				return fmt.Errorf("%s: cycle without a name", p.Name)
+			}
+			if seen[cycle.Cycle] {
+				return fmt.Errorf("%s: cycle %s is defined twice", p.Name, cycle.Cycle)
+			}
+			seen[cycle.Cycle] = true
+		}
+
+		if existing, ok := c.products[p.Name]; ok {
+			p.Cycles = mergeCycles(existing.Cycles, p.Cycles)
+			p.Replace = p.Replace || existing.Replace
+		}
+		c.products[p.Name] = p
+	}
+	return nil
+}
+
+// Products returns the names of every catalog product, sorted.
+func (c *Catalog) Products() []string {
+	names := make([]string, 0, len(c.products))
+	for name := range c.products {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the catalog entry for a product.
+func (c *Catalog) Lookup(name string) (Product, bool) {
+	p, ok := c.products[name]
+	return p, ok
+}
+
+// mergeCycles overlays override onto base: cycles present in both are merged
+// field by field, cycles only in override are appended.
+func mergeCycles(base, override []endoflife.Cycle) []endoflife.Cycle {
+	out := append([]endoflife.Cycle(nil), base...)
+	index := make(map[string]int, len(out))
+	for i, c := range out {
+		index[c.Cycle] = i
+	}
+	for _, o := range override {
+		if i, ok := index[o.Cycle]; ok {
+			out[i] = mergeCycle(out[i], o)
+			continue
+		}
+		index[o.Cycle] = len(out)
+		out = append(out, o)
+	}
+	return out
+}
+
+// mergeCycle returns base with every field that is set in override replaced.
+func mergeCycle(base, override endoflife.Cycle) endoflife.Cycle {
+	setString := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	setBoolOrDate := func(dst *endoflife.BoolOrDate, v endoflife.BoolOrDate) {
+		if v.IsSet() {
+			*dst = v
+		}
+	}
+
+	setString(&base.Codename, override.Codename)
+	setString(&base.ReleaseDate, override.ReleaseDate)
+	setBoolOrDate(&base.EOL, override.EOL)
+	setBoolOrDate(&base.Support, override.Support)
+	setBoolOrDate(&base.ExtendedSupport, override.ExtendedSupport)
+	setBoolOrDate(&base.LTS, override.LTS)
+	setBoolOrDate(&base.Discontinued, override.Discontinued)
+	setString(&base.Latest, override.Latest)
+	setString(&base.LatestReleaseDate, override.LatestReleaseDate)
+	setString(&base.Link, override.Link)
+	return base
+}
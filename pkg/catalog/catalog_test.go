@@ -0,0 +1,311 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+)
+
+// fakeProvider serves public products from memory and counts lookups.
+type fakeProvider struct {
+	products map[string][]endoflife.Cycle
+	calls    int
+}
+
+func (p *fakeProvider) GetAvailableProducts(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range p.products {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (p *fakeProvider) GetProduct(ctx context.Context, product string) ([]endoflife.Cycle, error) {
+	p.calls++
+	cycles, ok := p.products[product]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", endoflife.ErrNotFound, product)
+	}
+	return append([]endoflife.Cycle(nil), cycles...), nil
+}
+
+func (p *fakeProvider) GetCycle(ctx context.Context, product, cycle string) (*endoflife.Cycle, error) {
+	p.calls++
+	for _, c := range p.products[product] {
+		if c.Cycle == cycle {
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s", endoflife.ErrNotFound, product, cycle)
+}
+
+func date(t *testing.T, s string) endoflife.BoolOrDate {
+	t.Helper()
+	var b endoflife.BoolOrDate
+	if err := b.UnmarshalJSON([]byte(`"` + s + `"`)); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func publicProvider(t *testing.T) *fakeProvider {
+	return &fakeProvider{products: map[string][]endoflife.Cycle{
+		"rhel": {
+			{Cycle: "9", EOL: date(t, "2032-05-31"), Support: date(t, "2027-05-31"), ExtendedSupport: date(t, "2035-05-31"), Latest: "9.4"},
+			{Cycle: "8", EOL: date(t, "2029-05-31"), Support: date(t, "2024-05-31"), ExtendedSupport: date(t, "2032-05-31"), Latest: "8.10"},
+		},
+		"nodejs": {
+			{Cycle: "20", EOL: date(t, "2026-04-30"), Latest: "20.18.0"},
+		},
+	}}
+}
+
+func loadCatalog(t *testing.T, files ...string) *Catalog {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for i, content := range files {
+		path := filepath.Join(dir, fmt.Sprintf("catalog-%d.yaml", i))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	c, err := Load(paths...)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return c
+}
+
+const testCatalog = `
+products:
+  # An internal platform endoflife.date does not know.
+  - name: acme-runtime
+    cycles:
+      - cycle: "3"
+        eol: 2027-01-31
+        latest: "3.4.1"
+      - cycle: "2"
+        eol: true
+  # Our RHEL subscription includes extended support for RHEL 8 until 2031;
+  # every other field comes from the public data.
+  - name: rhel
+    cycles:
+      - cycle: "8"
+        extendedSupport: 2031-12-31
+      - cycle: "7"
+        eol: 2024-06-30
+        extendedSupport: 2028-06-30
+        latest: "7.9"
+  # A product whose public data is ignored entirely.
+  - name: nodejs
+    replace: true
+    cycles:
+      - cycle: "20"
+        eol: 2025-12-31
+`
+
+func TestOverlayGetProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		product string
+		// want is "cycle eol support extendedSupport latest" per cycle.
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "local-only product",
+			product: "acme-runtime",
+			want:    []string{"3 2027-01-31 - - 3.4.1", "2 true - - -"},
+		},
+		{
+			name:    "public product overridden field by field, local cycle added",
+			product: "rhel",
+			want: []string{
+				"9 2032-05-31 2027-05-31 2035-05-31 9.4",
+				"8 2029-05-31 2024-05-31 2031-12-31 8.10",
+				"7 2024-06-30 - 2028-06-30 7.9",
+			},
+		},
+		{
+			name:    "replaced public product",
+			product: "nodejs",
+			want:    []string{"20 2025-12-31 - - -"},
+		},
+		{
+			name:    "unknown product",
+			product: "cobol",
+			wantErr: endoflife.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Overlay{Base: publicProvider(t), Catalog: loadCatalog(t, testCatalog)}
+			cycles, err := o.GetProduct(context.Background(), tt.product)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetProduct error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetProduct: %v", err)
+			}
+			if got := describe(cycles); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("GetProduct(%s) =\n  %s\nwant\n  %s", tt.product, strings.Join(got, "\n  "), strings.Join(tt.want, "\n  "))
+			}
+		})
+	}
+}
+
+func TestOverlayGetCycle(t *testing.T) {
+	tests := []struct {
+		product string
+		cycle   string
+		want    string
+		wantErr error
+	}{
+		{"rhel", "8", "8 2029-05-31 2024-05-31 2031-12-31 8.10", nil},
+		{"rhel", "9", "9 2032-05-31 2027-05-31 2035-05-31 9.4", nil},
+		{"rhel", "7", "7 2024-06-30 - 2028-06-30 7.9", nil},
+		{"rhel", "6", "", endoflife.ErrNotFound},
+		{"acme-runtime", "3", "3 2027-01-31 - - 3.4.1", nil},
+		{"acme-runtime", "1", "", endoflife.ErrNotFound},
+		{"nodejs", "20", "20 2025-12-31 - - -", nil},
+		{"nodejs", "22", "", endoflife.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.product+"/"+tt.cycle, func(t *testing.T) {
+			o := &Overlay{Base: publicProvider(t), Catalog: loadCatalog(t, testCatalog)}
+			c, err := o.GetCycle(context.Background(), tt.product, tt.cycle)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetCycle error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCycle: %v", err)
+			}
+			if got := describe([]endoflife.Cycle{*c})[0]; got != tt.want {
+				t.Errorf("GetCycle = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlayDoesNotQueryBaseForReplacedProducts(t *testing.T) {
+	base := publicProvider(t)
+	o := &Overlay{Base: base, Catalog: loadCatalog(t, testCatalog)}
+	o.GetProduct(context.Background(), "nodejs")
+	o.GetCycle(context.Background(), "nodejs", "20")
+	if base.calls != 0 {
+		t.Errorf("base provider queried %d time(s) for a replaced product", base.calls)
+	}
+}
+
+func TestOverlayGetAvailableProducts(t *testing.T) {
+	tests := []struct {
+		name string
+		base endoflife.Provider
+		want string
+	}{
+		{"merged with the public list", publicProvider(t), "acme-runtime,nodejs,rhel"},
+		{"catalog only", nil, "acme-runtime,nodejs,rhel"},
+		{"public list only has other products", &fakeProvider{products: map[string][]endoflife.Cycle{"go": nil, "rhel": nil}}, "acme-runtime,go,nodejs,rhel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Overlay{Base: tt.base, Catalog: loadCatalog(t, testCatalog)}
+			got, err := o.GetAvailableProducts(context.Background())
+			if err != nil {
+				t.Fatalf("GetAvailableProducts: %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("GetAvailableProducts() = %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlayCatalogOnly(t *testing.T) {
+	o := &Overlay{Catalog: loadCatalog(t, testCatalog)}
+	cycles, err := o.GetProduct(context.Background(), "rhel")
+	if err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+	if got := strings.Join(describe(cycles), "; "); got != "8 - - 2031-12-31 -; 7 2024-06-30 - 2028-06-30 7.9" {
+		t.Errorf("GetProduct(rhel) = %s, want the catalog cycles alone", got)
+	}
+	if _, err := o.GetProduct(context.Background(), "go"); !errors.Is(err, endoflife.ErrNotFound) {
+		t.Errorf("GetProduct(go) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLoadMergesFiles(t *testing.T) {
+	c := loadCatalog(t, testCatalog, `
+products:
+  - name: acme-runtime
+    cycles:
+      - cycle: "3"
+        latest: "3.5.0"
+      - cycle: "4"
+        eol: false
+`)
+	p, ok := c.Lookup("acme-runtime")
+	if !ok {
+		t.Fatal("acme-runtime not in the catalog")
+	}
+	if got := strings.Join(describe(p.Cycles), "; "); got != "3 2027-01-31 - - 3.5.0; 2 true - - -; 4 false - - -" {
+		t.Errorf("merged cycles = %s", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"product without a name", `products: [{cycles: [{cycle: "1"}]}]`, "product without a name"},
+		{"cycle without a name", `products: [{name: x, cycles: [{eol: true}]}]`, "x: cycle without a name"},
+		{"duplicate cycle", `products: [{name: x, cycles: [{cycle: "1"}, {cycle: "1"}]}]`, "x: cycle 1 is defined twice"},
+		{"invalid date", `products: [{name: x, cycles: [{cycle: "1", eol: "next year"}]}]`, "failed to parse catalog"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "catalog.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// describe renders the fields the tests check, with "-" for unset ones.
+func describe(cycles []endoflife.Cycle) []string {
+	field := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	out := make([]string, 0, len(cycles))
+	for _, c := range cycles {
+		out = append(out, strings.Join([]string{
+			c.Cycle, field(c.EOL.String()), field(c.Support.String()), field(c.ExtendedSupport.String()), field(c.Latest),
+		}, " "))
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+)
+
+// Overlay is an endoflife.Provider that consults a catalog before its base
+// provider. Catalog products unknown to the base are served from the catalog
+// alone; known products have the catalog cycles merged over the public ones
+// unless the catalog entry replaces them. A nil Base serves the catalog only.
+type Overlay struct {
+	Base    endoflife.Provider
+	Catalog *Catalog
+}
+
+var _ endoflife.Provider = (*Overlay)(nil)
+
+func (o *Overlay) GetAvailableProducts(ctx context.Context) ([]string, error) {
+	names := o.Catalog.Products()
+	if o.Base == nil {
+		return names, nil
+	}
+
+	public, err := o.Base.GetAvailableProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(public))
+	for _, name := range public {
+		seen[name] = true
+	}
+	merged := append([]string(nil), public...)
+	for _, name := range names {
+		if !seen[name] {
+			merged = append(merged, name)
+		}
+	}
+	sort.Strings(merged)
+	return merged, nil
+}
+
+func (o *Overlay) GetProduct(ctx context.Context, product string) ([]endoflife.Cycle, error) {
+	local, ok := o.Catalog.Lookup(product)
+	if !ok {
+		if o.Base == nil {
+			return nil, fmt.Errorf("%w: %s is not in the catalog", endoflife.ErrNotFound, product)
+		}
+		return o.Base.GetProduct(ctx, product)
+	}
+	if local.Replace || o.Base == nil {
+		return append([]endoflife.Cycle(nil), local.Cycles...), nil
+	}
+
+	public, err := o.Base.GetProduct(ctx, product)
+	if err != nil && !errors.Is(err, endoflife.ErrNotFound) {
+		return nil, err
+	}
+	return mergeCycles(public, local.Cycles), nil
+}
+
+func (o *Overlay) GetCycle(ctx context.Context, product, cycle string) (*endoflife.Cycle, error) {
+	local, ok := o.Catalog.Lookup(product)
+	if !ok {
+		if o.Base == nil {
+			return nil, fmt.Errorf("%w: %s is not in the catalog", endoflife.ErrNotFound, product)
+		}
+		return o.Base.GetCycle(ctx, product, cycle)
+	}
+
+	var override *endoflife.Cycle
+	for i := range local.Cycles {
+		if local.Cycles[i].Cycle == cycle {
+			c := local.Cycles[i]
+			override = &c
+			break
+		}
+	}
+
+	if local.Replace || o.Base == nil {
+		if override == nil {
+			return nil, fmt.Errorf("%w: %s %s is not in the catalog", endoflife.ErrNotFound, product, cycle)
+		}
+		return override, nil
+	}
+
+	public, err := o.Base.GetCycle(ctx, product, cycle)
+	switch {
+	case err == nil && override != nil:
+		merged := mergeCycle(*public, *override)
+		return &merged, nil
+	case err == nil:
+		return public, nil
+	case errors.Is(err, endoflife.ErrNotFound) && override != nil:
+		return override, nil
+	default:
+		return nil, err
+	}
+}
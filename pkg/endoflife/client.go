@@ -0,0 +1,121 @@
+package endoflife
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the public endoflife.date API.
+const DefaultBaseURL = "https://endoflife.date/api"
+
+// ErrNotFound is returned when the API has no data for the requested product or cycle.
+var ErrNotFound = errors.New("not found")
+
+// Client is a typed client for the endoflife.date API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at a different API root, e.g. a mirror.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient returns a Client for the public API unless overridden by opts.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the API root the client talks to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// GetAvailableProducts returns the slugs of every product tracked by the API.
+func (c *Client) GetAvailableProducts(ctx context.Context) ([]string, error) {
+	var products []string
+	if err := c.get(ctx, "/all.json", &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetProduct returns every release cycle of product.
+func (c *Client) GetProduct(ctx context.Context, product string) ([]Cycle, error) {
+	var cycles []Cycle
+	if err := c.get(ctx, fmt.Sprintf("/%s.json", url.PathEscape(product)), &cycles); err != nil {
+		return nil, err
+	}
+	return cycles, nil
+}
+
+// GetCycle returns a single release cycle of product. The API omits the cycle
+// name from single-cycle responses, so it is filled in from the argument.
+func (c *Client) GetCycle(ctx context.Context, product, cycle string) (*Cycle, error) {
+	var result Cycle
+	if err := c.get(ctx, fmt.Sprintf("/%s/%s.json", url.PathEscape(product), url.PathEscape(cycle)), &result); err != nil {
+		return nil, err
+	}
+	if result.Cycle == "" {
+		result.Cycle = cycle
+	}
+	return &result, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned non-200 status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse API response: %w", err)
+	}
+	return nil
+}
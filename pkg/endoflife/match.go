@@ -0,0 +1,159 @@
+package endoflife
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cycleComponents is how many leading version components name a release
+// cycle for products with a known scheme. Other products are matched by the
+// longest cycle whose components prefix the version.
+var cycleComponents = map[string]int{
+	"nodejs":     1, // 20.11.1 -> 20
+	"debian":     1, // 12.5 -> 12
+	"rhel":       1, // 8.10 -> 8
+	"postgresql": 1, // 16.4 -> 16; 9.6.24 falls back to the 9.6 prefix match
+	"python":     2, // 3.11.4 -> 3.11
+	"go":         2, // 1.23.2 -> 1.23
+	"kubernetes": 2, // 1.31.1 -> 1.31
+	"php":        2,
+	"ruby":       2,
+	"ubuntu":     2, // calendar versions: 22.04.3 -> 22.04
+}
+
+// MatchCycle returns the cycle of product that version belongs to. version
+// may be a cycle name, a codename, or a full release such as "1.25.3",
+// "v20.11.1", "3.11.4rc1" or "22.04.3"; components are compared numerically,
+// so "22.4" matches the "22.04" cycle.
+func MatchCycle(product, version string, cycles []Cycle) (*Cycle, bool) {
+	want := strings.TrimSpace(version)
+	for i := range cycles {
+		if strings.EqualFold(cycles[i].Cycle, want) || matchCodename(cycles[i].Codename, want) {
+			return &cycles[i], true
+		}
+	}
+
+	v := versionComponents(want)
+	if len(v) == 0 {
+		return nil, false
+	}
+
+	if n, ok := cycleComponents[product]; ok && len(v) >= n {
+		for i := range cycles {
+			if equalComponents(versionComponents(cycles[i].Cycle), v[:n]) {
+				return &cycles[i], true
+			}
+		}
+	}
+
+	var best *Cycle
+	bestLen := 0
+	for i := range cycles {
+		c := versionComponents(cycles[i].Cycle)
+		if len(c) == 0 || len(c) > len(v) || len(c) <= bestLen {
+			continue
+		}
+		if equalComponents(c, v[:len(c)]) {
+			best, bestLen = &cycles[i], len(c)
+		}
+	}
+	return best, best != nil
+}
+
+// FindCycle fetches product from p and returns the cycle version belongs to,
+// wrapping ErrNotFound when the product or a matching cycle is unknown.
+func FindCycle(ctx context.Context, p Provider, product, version string) (*Cycle, error) {
+	cycles, err := p.GetProduct(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := MatchCycle(product, version, cycles); ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: no %s cycle matches version %s", ErrNotFound, product, version)
+}
+
+// ReleasesBehind returns how many releases version trails the latest release
+// of cycle c, counted on the first component below the cycle that differs.
+// For major.minor cycles those are patch releases: go 1.22.0 against a
+// latest of 1.22.12 is 12 behind. For major-only cycles a newer minor
+// release counts once per minor and its patches are not counted: nodejs
+// 20.11.1 against 20.18.0 is 7 behind, 20.18.0 against 20.18.1 is 1. It
+// reports false when version names only the cycle or either version cannot
+// be parsed.
+func ReleasesBehind(version string, c Cycle) (int, bool) {
+	depth := len(versionComponents(c.Cycle))
+	installed := versionComponents(version)
+	latest := versionComponents(c.Latest)
+	if len(installed) <= depth || len(latest) <= depth {
+		return 0, false
+	}
+
+	for i := depth; i < max(len(installed), len(latest)); i++ {
+		var have, want int
+		if i < len(installed) {
+			have = installed[i]
+		}
+		if i < len(latest) {
+			want = latest[i]
+		}
+		if have != want {
+			return max(want-have, 0), true
+		}
+	}
+	return 0, true
+}
+
+// versionComponents returns the leading numeric components of a version,
+// ignoring constraint operators and a "v" prefix and stopping at the first
+// pre-release or build suffix: "^v3.11.4rc1" -> [3 11 4].
+func versionComponents(version string) []int {
+	s := strings.TrimLeft(version, "^~>=<! vV")
+
+	var out []int
+	for _, part := range strings.Split(s, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, err := strconv.Atoi(part[:end])
+		if err != nil {
+			break
+		}
+		out = append(out, n)
+		if end < len(part) {
+			break
+		}
+	}
+	return out
+}
+
+// matchCodename reports whether want names codename in full or by its first
+// word, as in "jammy" for "Jammy Jellyfish".
+func matchCodename(codename, want string) bool {
+	if codename == "" || want == "" {
+		return false
+	}
+	if strings.EqualFold(codename, want) {
+		return true
+	}
+	first, _, _ := strings.Cut(codename, " ")
+	return strings.EqualFold(first, want)
+}
+
+func equalComponents(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package endoflife
+
+import "testing"
+
+func TestMatchCycle(t *testing.T) {
+	nodejs := []Cycle{{Cycle: "22"}, {Cycle: "20", Codename: "Iron"}, {Cycle: "18"}}
+	python := []Cycle{{Cycle: "3.12"}, {Cycle: "3.11"}, {Cycle: "3.1"}}
+	ubuntu := []Cycle{{Cycle: "24.04", Codename: "Noble Numbat"}, {Cycle: "22.04", Codename: "Jammy Jellyfish"}}
+	postgresql := []Cycle{{Cycle: "16"}, {Cycle: "9.6"}}
+	other := []Cycle{{Cycle: "1"}, {Cycle: "1.25"}, {Cycle: "1.2"}}
+
+	tests := []struct {
+		product string
+		version string
+		cycles  []Cycle
+		want    string
+	}{
+		{"nodejs", "v20.11.1", nodejs, "20"},
+		{"nodejs", "20", nodejs, "20"},
+		{"nodejs", "iron", nodejs, "20"},
+		{"nodejs", "^18.2", nodejs, "18"},
+		{"nodejs", "16.20.2", nodejs, ""},
+		{"python", "3.11.4rc1", python, "3.11"},
+		{"python", "3.1.5", python, "3.1"},
+		{"python", "3.10.1", python, ""},
+		{"python", "3", python, ""},
+		{"ubuntu", "22.04.3", ubuntu, "22.04"},
+		{"ubuntu", "22.4", ubuntu, "22.04"},
+		{"ubuntu", "jammy", ubuntu, "22.04"},
+		{"ubuntu", "Noble Numbat", ubuntu, "24.04"},
+		{"postgresql", "16.4", postgresql, "16"},
+		{"postgresql", "9.6.24", postgresql, "9.6"},
+		{"nginx", "1.25.3", other, "1.25"},
+		{"nginx", "1.26.0", other, "1"},
+		{"nginx", "2.0", other, ""},
+		{"nginx", "latest", other, ""},
+		{"nginx", "", other, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.product+"@"+tt.version, func(t *testing.T) {
+			c, ok := MatchCycle(tt.product, tt.version, tt.cycles)
+			var got string
+			if ok {
+				got = c.Cycle
+			}
+			if got != tt.want {
+				t.Errorf("MatchCycle(%q, %q) = %q, want %q", tt.product, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleasesBehind(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		cycle   Cycle
+		want    int
+		wantOK  bool
+	}{
+		{"patch releases", "1.22.0", Cycle{Cycle: "1.22", Latest: "1.22.12"}, 12, true},
+		{"v prefix", "v20.11.1", Cycle{Cycle: "20", Latest: "20.11.3"}, 2, true},
+		{"minor releases of a major-only cycle", "20.11.1", Cycle{Cycle: "20", Latest: "20.18.0"}, 7, true},
+		{"patch within the latest minor", "20.18.0", Cycle{Cycle: "20", Latest: "20.18.1"}, 1, true},
+		{"pre-release", "3.11.4rc1", Cycle{Cycle: "3.11", Latest: "3.11.9"}, 5, true},
+		{"calendar version", "22.04.3", Cycle{Cycle: "22.04", Latest: "22.04.5"}, 2, true},
+		{"point release", "12.5", Cycle{Cycle: "12", Latest: "12.7"}, 2, true},
+		{"missing patch component", "1.22", Cycle{Cycle: "1", Latest: "1.22.3"}, 3, true},
+		{"current", "1.22.12", Cycle{Cycle: "1.22", Latest: "1.22.12"}, 0, true},
+		{"ahead of latest", "1.22.13", Cycle{Cycle: "1.22", Latest: "1.22.12"}, 0, true},
+		{"cycle only", "1.22", Cycle{Cycle: "1.22", Latest: "1.22.12"}, 0, false},
+		{"latest is the cycle", "1.22.1", Cycle{Cycle: "1.22", Latest: "1.22"}, 0, false},
+		{"unparsable latest", "1.22.1", Cycle{Cycle: "1.22", Latest: ""}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ReleasesBehind(tt.version, tt.cycle)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ReleasesBehind(%q, %s/%s) = %d, %v; want %d, %v", tt.version, tt.cycle.Cycle, tt.cycle.Latest, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
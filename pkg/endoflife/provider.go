@@ -0,0 +1,13 @@
+package endoflife
+
+import "context"
+
+// Provider is a read-only source of endoflife.date data. It is implemented
+// by the live Client as well as by offline sources such as snapshot bundles.
+type Provider interface {
+	GetAvailableProducts(ctx context.Context) ([]string, error)
+	GetProduct(ctx context.Context, product string) ([]Cycle, error)
+	GetCycle(ctx context.Context, product, cycle string) (*Cycle, error)
+}
+
+var _ Provider = (*Client)(nil)
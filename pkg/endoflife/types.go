@@ -0,0 +1,161 @@
+package endoflife
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout is the date format used by the endoflife.date API.
+const DateLayout = "2006-01-02"
+
+// BoolOrDate holds one of the endoflife.date fields (eol, support, lts, ...)
+// that may be either a boolean or an ISO date such as "2025-04-30". Any other
+// string is rejected when decoding.
+type BoolOrDate struct {
+	Bool bool
+	Date time.Time
+
+	raw string // original string form when the value was not a boolean
+	set bool
+}
+
+// NewBool returns a BoolOrDate holding a boolean value.
+func NewBool(v bool) BoolOrDate {
+	return BoolOrDate{Bool: v, set: true}
+}
+
+// NewDate returns a BoolOrDate holding a date value.
+func NewDate(t time.Time) BoolOrDate {
+	return BoolOrDate{Date: t, raw: t.Format(DateLayout), set: true}
+}
+
+// IsSet reports whether the field was present and non-null.
+func (b BoolOrDate) IsSet() bool {
+	return b.set
+}
+
+// IsDate reports whether the value is a parseable date.
+func (b BoolOrDate) IsDate() bool {
+	return !b.Date.IsZero()
+}
+
+// IsBool reports whether the value is a boolean.
+func (b BoolOrDate) IsBool() bool {
+	return b.set && b.raw == ""
+}
+
+// String returns the value as it appears in the API: the date, "true"/"false",
+// or an empty string when unset.
+func (b BoolOrDate) String() string {
+	switch {
+	case !b.set:
+		return ""
+	case b.raw != "":
+		return b.raw
+	case b.Bool:
+		return "true"
+	default:
+		return "false"
+	}
+}
+
+func (b *BoolOrDate) UnmarshalJSON(data []byte) error {
+	*b = BoolOrDate{}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	var boolVal bool
+	if err := json.Unmarshal(data, &boolVal); err == nil {
+		b.Bool = boolVal
+		b.set = true
+		return nil
+	}
+
+	var stringVal string
+	if err := json.Unmarshal(data, &stringVal); err == nil {
+		if stringVal == "" {
+			return nil
+		}
+		date, err := time.Parse(DateLayout, stringVal)
+		if err != nil {
+			return fmt.Errorf("invalid bool-or-date value %q: dates must use the YYYY-MM-DD format", stringVal)
+		}
+		b.Date = date
+		b.raw = stringVal
+		b.set = true
+		return nil
+	}
+
+	return fmt.Errorf("invalid bool-or-date value: %s", string(data))
+}
+
+func (b BoolOrDate) MarshalJSON() ([]byte, error) {
+	switch {
+	case !b.set:
+		return []byte("null"), nil
+	case b.raw != "":
+		return json.Marshal(b.raw)
+	default:
+		return json.Marshal(b.Bool)
+	}
+}
+
+// Cycle is a single release cycle of a product as returned by the
+// endoflife.date API.
+type Cycle struct {
+	Cycle             string     `json:"cycle"`
+	Codename          string     `json:"codename,omitempty"`
+	ReleaseDate       string     `json:"releaseDate"`
+	EOL               BoolOrDate `json:"eol"`
+	Support           BoolOrDate `json:"support"`
+	ExtendedSupport   BoolOrDate `json:"extendedSupport"`
+	LTS               BoolOrDate `json:"lts"`
+	Discontinued      BoolOrDate `json:"discontinued"`
+	Latest            string     `json:"latest"`
+	LatestReleaseDate string     `json:"latestReleaseDate"`
+	Link              string     `json:"link,omitempty"`
+}
+
+func (c *Cycle) UnmarshalJSON(data []byte) error {
+	// cycle, latest and codename are strings for almost every product, but a
+	// handful of entries use bare numbers or null.
+	type alias Cycle
+	aux := struct {
+		*alias
+		Cycle    json.RawMessage `json:"cycle"`
+		Codename json.RawMessage `json:"codename"`
+		Latest   json.RawMessage `json:"latest"`
+		Link     json.RawMessage `json:"link"`
+	}{alias: (*alias)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.Cycle = rawToString(aux.Cycle)
+	c.Codename = rawToString(aux.Codename)
+	c.Latest = rawToString(aux.Latest)
+	c.Link = rawToString(aux.Link)
+	return nil
+}
+
+func rawToString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+
+	return string(raw)
+}
@@ -0,0 +1,128 @@
+package endoflife
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBoolOrDateUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantSet    bool
+		wantBool   bool
+		wantDate   string
+		wantString string
+		wantErr    string
+	}{
+		{name: "true", data: `true`, wantSet: true, wantBool: true, wantString: "true"},
+		{name: "false", data: `false`, wantSet: true, wantString: "false"},
+		{name: "date", data: `"2025-04-30"`, wantSet: true, wantDate: "2025-04-30", wantString: "2025-04-30"},
+		{name: "null", data: `null`},
+		{name: "empty string", data: `""`},
+		{name: "invalid date", data: `"2025-13-01"`, wantErr: `invalid bool-or-date value "2025-13-01"`},
+		{name: "not a date", data: `"soon"`, wantErr: `invalid bool-or-date value "soon"`},
+		{name: "number", data: `2025`, wantErr: "invalid bool-or-date value: 2025"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b BoolOrDate
+			err := json.Unmarshal([]byte(tt.data), &b)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Unmarshal(%s) error = %v, want %q", tt.data, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.data, err)
+			}
+
+			var date string
+			if b.IsDate() {
+				date = b.Date.Format(DateLayout)
+			}
+			if b.IsSet() != tt.wantSet || b.Bool != tt.wantBool || date != tt.wantDate || b.String() != tt.wantString {
+				t.Errorf("Unmarshal(%s) = set %v, bool %v, date %q, string %q", tt.data, b.IsSet(), b.Bool, date, b.String())
+			}
+			if b.IsBool() == b.IsDate() && tt.wantSet {
+				t.Errorf("Unmarshal(%s): IsBool %v and IsDate %v, want exactly one", tt.data, b.IsBool(), b.IsDate())
+			}
+
+			out, err := json.Marshal(b)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if want := tt.data; want == `""` {
+				if string(out) != "null" {
+					t.Errorf("Marshal = %s, want null", out)
+				}
+			} else if string(out) != want {
+				t.Errorf("Marshal = %s, want %s", out, want)
+			}
+		})
+	}
+}
+
+func TestCycleUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Cycle
+		wantEOL string
+		wantErr string
+	}{
+		{
+			name:    "strings",
+			data:    `{"cycle": "3.12", "latest": "3.12.4", "codename": "", "eol": "2028-10-31", "lts": false}`,
+			want:    Cycle{Cycle: "3.12", Latest: "3.12.4"},
+			wantEOL: "2028-10-31",
+		},
+		{
+			name:    "numeric cycle and latest",
+			data:    `{"cycle": 8, "latest": 8.1, "eol": true}`,
+			want:    Cycle{Cycle: "8", Latest: "8.1"},
+			wantEOL: "true",
+		},
+		{
+			name: "decimal cycle keeps its digits",
+			data: `{"cycle": 3.10, "latest": "3.10.14"}`,
+			want: Cycle{Cycle: "3.10", Latest: "3.10.14"},
+		},
+		{
+			name: "missing and null fields",
+			data: `{"cycle": "20", "latest": null, "codename": null, "eol": null}`,
+			want: Cycle{Cycle: "20"},
+		},
+		{
+			name:    "invalid date",
+			data:    `{"cycle": "1", "eol": "31/12/2025"}`,
+			wantErr: `invalid bool-or-date value "31/12/2025"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Cycle
+			err := json.Unmarshal([]byte(tt.data), &c)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Unmarshal error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if c.Cycle != tt.want.Cycle || c.Latest != tt.want.Latest || c.Codename != tt.want.Codename {
+				t.Errorf("Unmarshal = cycle %q, latest %q, codename %q; want %q, %q, %q", c.Cycle, c.Latest, c.Codename, tt.want.Cycle, tt.want.Latest, tt.want.Codename)
+			}
+			if c.EOL.String() != tt.wantEOL {
+				t.Errorf("eol = %q, want %q", c.EOL.String(), tt.wantEOL)
+			}
+			if c.Support.IsSet() || c.ExtendedSupport.IsSet() {
+				t.Errorf("missing fields are set: support %q, extendedSupport %q", c.Support, c.ExtendedSupport)
+			}
+		})
+	}
+}
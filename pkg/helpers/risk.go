@@ -1,6 +1,11 @@
 package helpers
 
-import "time"
+import (
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+	"github.com/asafdavid23/eolctl/pkg/policy"
+)
 
 type RiskLevel string
 
@@ -12,42 +17,183 @@ const (
 	RiskUnknown  RiskLevel = "UNKNOWN"
 )
 
+// SupportPhase is where a cycle stands in its vendor's support lifecycle.
+type SupportPhase string
+
+const (
+	// PhaseActive: bug fixes and security fixes.
+	PhaseActive SupportPhase = "active"
+	// PhaseSecurityOnly: active support has ended, security fixes continue
+	// until EOL.
+	PhaseSecurityOnly SupportPhase = "security-only"
+	// PhaseExtended: past EOL, covered by (usually paid) extended support.
+	PhaseExtended SupportPhase = "extended-support"
+	// PhaseEOL: no support of any kind.
+	PhaseEOL     SupportPhase = "eol"
+	PhaseUnknown SupportPhase = "unknown"
+)
+
 type RiskInfo struct {
 	Level        RiskLevel
 	DaysUntilEOL int
-	EOLDate      string
+	// Dated reports whether DaysUntilEOL counts down to a known date; it is
+	// false when there is no EOL date or it cannot be determined.
+	Dated bool
+	// EOLDate describes the date DaysUntilEOL counts down to: the EOL date,
+	// or the end of extended support when that is counted as supported.
+	EOLDate string
+	Phase   SupportPhase
 }
 
-func daysToRisk(ds int) RiskLevel {
+// CalculateRisk rates an EOL date with the built-in policy.
+func CalculateRisk(eol endoflife.BoolOrDate) RiskInfo {
+	return CalculateRiskAt(eol, time.Now())
+}
+
+// CalculateRiskAt rates an EOL date with the built-in policy as of now.
+func CalculateRiskAt(eol endoflife.BoolOrDate, now time.Time) RiskInfo {
+	return eolRisk(eol, policy.Default().Rules, policy.Unknown, now)
+}
+
+func eolRisk(eol endoflife.BoolOrDate, rules policy.Rules, unknown string, now time.Time) RiskInfo {
 	switch {
-	case ds <= 0:
-		return RiskCritical
-	case ds <= 90:
-		return RiskHigh
-	case ds <= 180:
-		return RiskMedium
-	default:
-		return RiskLow
+	case eol.IsBool():
+		if eol.Bool {
+			return RiskInfo{Level: RiskLevel(rules.ForDays(0)), DaysUntilEOL: 0, Dated: true, EOLDate: "already EOL"}
+		} else {
+			return RiskInfo{Level: RiskLevel(rules.Default), DaysUntilEOL: -1, EOLDate: "no known EOL date"}
+		}
+	case eol.IsDate():
+		days := int(eol.Date.Sub(now).Hours() / 24)
+		return RiskInfo{Level: RiskLevel(rules.ForDays(days)), DaysUntilEOL: days, Dated: true, EOLDate: eol.String()}
+	case eol.IsSet():
+		return RiskInfo{Level: RiskLevel(unknown), DaysUntilEOL: -1, EOLDate: eol.String()}
 	}
+	return RiskInfo{Level: RiskLevel(unknown), DaysUntilEOL: -1, EOLDate: "unknown"}
 }
 
-func CalculateRisk(eolValue interface{}) RiskInfo {
-	switch v := eolValue.(type) {
-	case bool:
-		if v {
-			return RiskInfo{Level: RiskCritical, DaysUntilEOL: 0, EOLDate: "already EOL"}
-		} else {
-			return RiskInfo{Level: RiskLow, DaysUntilEOL: -1, EOLDate: "no known EOL date"}
+// Evaluator derives risk from every support dimension of a cycle.
+type Evaluator struct {
+	// Policy maps remaining days and staleness to levels; nil means the
+	// built-in policy.
+	Policy *policy.Policy
+	// CountExtendedSupport treats paid extended support as supported: a
+	// cycle past EOL but within extended support is rated by the days left
+	// until extended support ends instead of as EOL.
+	CountExtendedSupport bool
+	// Now returns the moment risk is evaluated at; nil means time.Now. Set
+	// it to evaluate as of a past or future date.
+	Now func() time.Time
+}
+
+func (e Evaluator) now() time.Time {
+	if e.Now == nil {
+		return time.Now()
+	}
+	return e.Now()
+}
+
+func (e Evaluator) policy() *policy.Policy {
+	if e.Policy == nil {
+		return policy.Default()
+	}
+	return e.Policy
+}
+
+// Rules returns the policy rules that apply to scope.
+func (e Evaluator) Rules(scope policy.Scope) policy.Rules {
+	return e.policy().For(scope)
+}
+
+// Evaluate returns the risk of running cycle c in scope. Level and
+// DaysUntilEOL follow the EOL date, or the extended support date for cycles
+// in extended support when CountExtendedSupport is set; Phase records
+// whether the cycle is in active support, security-only, extended support
+// or fully EOL.
+func (e Evaluator) Evaluate(c endoflife.Cycle, scope policy.Scope) RiskInfo {
+	now := e.now()
+	rules := e.Rules(scope)
+	info := eolRisk(c.EOL, rules, e.policy().UnknownLevel, now)
+	info.Phase = phase(c, now)
+
+	if info.Phase == PhaseExtended && e.CountExtendedSupport {
+		switch {
+		case c.ExtendedSupport.IsDate():
+			days := int(c.ExtendedSupport.Date.Sub(now).Hours() / 24)
+			info.Level = RiskLevel(rules.ForDays(days))
+			info.DaysUntilEOL = days
+			info.Dated = true
+			info.EOLDate = c.ExtendedSupport.String()
+		default:
+			info.Level = RiskLevel(rules.Default)
+			info.DaysUntilEOL = -1
+			info.Dated = false
 		}
-	case string:
-		eolDate, err := time.Parse("2006-01-02", v)
+	}
+	return info
+}
 
-		if err != nil {
-			return RiskInfo{Level: RiskUnknown, DaysUntilEOL: -1, EOLDate: v}
+// PatchRisk compares the installed version against c.Latest. Being behind
+// within a supported cycle is rated by the policy's patch rules, which by
+// default never reach CRITICAL; that level is reserved for EOL.
+func (e Evaluator) PatchRisk(installed string, c endoflife.Cycle, scope policy.Scope) PatchInfo {
+	behind, ok := endoflife.ReleasesBehind(installed, c)
+	if !ok {
+		return PatchInfo{Latest: c.Latest, Level: RiskLevel(e.policy().UnknownLevel)}
+	}
+	return PatchInfo{Latest: c.Latest, ReleasesBehind: &behind, Level: RiskLevel(e.Rules(scope).ForPatches(behind))}
+}
+
+// phase works out the support phase of c at now from its support, eol and
+// extendedSupport fields.
+func phase(c endoflife.Cycle, now time.Time) SupportPhase {
+	var eolReached bool
+	switch {
+	case c.EOL.IsDate():
+		eolReached = !now.Before(c.EOL.Date)
+	case c.EOL.IsBool():
+		eolReached = c.EOL.Bool
+	default:
+		return PhaseUnknown
+	}
+
+	if eolReached {
+		if ongoing, _ := ongoing(c.ExtendedSupport, now); ongoing {
+			return PhaseExtended
 		}
+		return PhaseEOL
+	}
+	if ongoing, known := ongoing(c.Support, now); known && !ongoing {
+		return PhaseSecurityOnly
+	}
+	return PhaseActive
+}
 
-		days := int(time.Until(eolDate).Hours() / 24)
-		return RiskInfo{Level: daysToRisk(days), DaysUntilEOL: days, EOLDate: v}
+// ongoing reports whether a support window given as an end date or a
+// boolean is still open at now.
+func ongoing(v endoflife.BoolOrDate, now time.Time) (open bool, known bool) {
+	switch {
+	case v.IsDate():
+		return now.Before(v.Date), true
+	case v.IsBool():
+		return v.Bool, true
+	default:
+		return false, false
 	}
-	return RiskInfo{Level: RiskUnknown, DaysUntilEOL: -1, EOLDate: "unknown"}
+}
+
+// PatchInfo describes how far an installed version trails the latest
+// release of its cycle.
+type PatchInfo struct {
+	Latest string
+	// ReleasesBehind is nil when the installed version names only the cycle;
+	// see endoflife.ReleasesBehind for how releases are counted.
+	ReleasesBehind *int
+	Level          RiskLevel
+}
+
+// CalculatePatchRisk compares the installed version against cycle.Latest
+// with the built-in policy.
+func CalculatePatchRisk(installed string, cycle endoflife.Cycle) PatchInfo {
+	return Evaluator{}.PatchRisk(installed, cycle, policy.Scope{})
 }
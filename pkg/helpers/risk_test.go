@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/endoflife"
+	"github.com/asafdavid23/eolctl/pkg/policy"
+)
+
+func TestEvaluatorEvaluate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := func(days int) endoflife.BoolOrDate { return endoflife.NewDate(now.AddDate(0, 0, days)) }
+	yes, no := endoflife.NewBool(true), endoflife.NewBool(false)
+
+	tests := []struct {
+		name      string
+		cycle     endoflife.Cycle
+		countExt  bool
+		wantLevel RiskLevel
+		wantDays  int
+		wantDated bool
+		wantDate  string
+		wantPhase SupportPhase
+	}{
+		{"EOL today", endoflife.Cycle{EOL: date(0)}, false, RiskCritical, 0, true, "2026-01-01", PhaseEOL},
+		{"EOL passed", endoflife.Cycle{EOL: date(-30)}, false, RiskCritical, -30, true, "2025-12-02", PhaseEOL},
+		{"EOL tomorrow", endoflife.Cycle{EOL: date(1)}, false, RiskHigh, 1, true, "2026-01-02", PhaseActive},
+		{"90 days left", endoflife.Cycle{EOL: date(90)}, false, RiskHigh, 90, true, "2026-04-01", PhaseActive},
+		{"91 days left", endoflife.Cycle{EOL: date(91)}, false, RiskMedium, 91, true, "2026-04-02", PhaseActive},
+		{"180 days left", endoflife.Cycle{EOL: date(180)}, false, RiskMedium, 180, true, "2026-06-30", PhaseActive},
+		{"181 days left", endoflife.Cycle{EOL: date(181)}, false, RiskLow, 181, true, "2026-07-01", PhaseActive},
+		{"EOL true", endoflife.Cycle{EOL: yes}, false, RiskCritical, 0, true, "already EOL", PhaseEOL},
+		{"no EOL date", endoflife.Cycle{EOL: no}, false, RiskLow, -1, false, "no known EOL date", PhaseActive},
+		{"EOL unset", endoflife.Cycle{}, false, RiskUnknown, -1, false, "unknown", PhaseUnknown},
+
+		{"active support", endoflife.Cycle{EOL: date(400), Support: date(10)}, false, RiskLow, 400, true, "2027-02-05", PhaseActive},
+		{"security only", endoflife.Cycle{EOL: date(400), Support: date(-10)}, false, RiskLow, 400, true, "2027-02-05", PhaseSecurityOnly},
+		{"active support ended", endoflife.Cycle{EOL: date(400), Support: no}, false, RiskLow, 400, true, "2027-02-05", PhaseSecurityOnly},
+		{"extended support ended", endoflife.Cycle{EOL: date(-400), ExtendedSupport: date(-10)}, false, RiskCritical, -400, true, "2024-11-27", PhaseEOL},
+
+		{"extended support not counted", endoflife.Cycle{EOL: date(-30), ExtendedSupport: date(180)}, false, RiskCritical, -30, true, "2025-12-02", PhaseExtended},
+		{"extended support counted", endoflife.Cycle{EOL: date(-30), ExtendedSupport: date(180)}, true, RiskMedium, 180, true, "2026-06-30", PhaseExtended},
+		{"extended support counted, ending soon", endoflife.Cycle{EOL: date(-30), ExtendedSupport: date(60)}, true, RiskHigh, 60, true, "2026-03-02", PhaseExtended},
+		{"extended support without end date", endoflife.Cycle{EOL: date(-30), ExtendedSupport: yes}, true, RiskLow, -1, false, "2025-12-02", PhaseExtended},
+		{"counting has no effect before EOL", endoflife.Cycle{EOL: date(30), ExtendedSupport: date(400)}, true, RiskHigh, 30, true, "2026-01-31", PhaseActive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Evaluator{CountExtendedSupport: tt.countExt, Now: func() time.Time { return now }}
+			got := e.Evaluate(tt.cycle, policy.Scope{})
+			if got.Level != tt.wantLevel || got.Phase != tt.wantPhase {
+				t.Errorf("Evaluate() level, phase = %s, %s; want %s, %s", got.Level, got.Phase, tt.wantLevel, tt.wantPhase)
+			}
+			if got.DaysUntilEOL != tt.wantDays || got.Dated != tt.wantDated || got.EOLDate != tt.wantDate {
+				t.Errorf("Evaluate() days, dated, date = %d, %v, %q; want %d, %v, %q",
+					got.DaysUntilEOL, got.Dated, got.EOLDate, tt.wantDays, tt.wantDated, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestEvaluatorPolicyOverride(t *testing.T) {
+	p, err := policy.Parse([]byte(`{
+		"levels": ["P1", "P2", "P3"],
+		"thresholds": [{"level": "P1", "max_days": 30}],
+		"default": "P3",
+		"overrides": [{"products": ["nodejs"], "thresholds": [{"level": "P1", "max_days": 365}]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := Evaluator{Policy: p, Now: func() time.Time { return now }}
+	cycle := endoflife.Cycle{EOL: endoflife.NewDate(now.AddDate(0, 0, 100))}
+
+	if got := e.Evaluate(cycle, policy.Scope{Product: "python"}).Level; got != "P3" {
+		t.Errorf("python level = %s, want P3", got)
+	}
+	if got := e.Evaluate(cycle, policy.Scope{Product: "nodejs"}).Level; got != "P1" {
+		t.Errorf("nodejs level = %s, want P1 from the override", got)
+	}
+	if got := e.Evaluate(endoflife.Cycle{}, policy.Scope{}).Level; got != RiskUnknown {
+		t.Errorf("unknown level = %s, want %s", got, RiskUnknown)
+	}
+}
+
+func TestEvaluatorPatchRisk(t *testing.T) {
+	cycle := endoflife.Cycle{Cycle: "1.22", Latest: "1.22.12"}
+	tests := []struct {
+		installed  string
+		wantLevel  RiskLevel
+		wantBehind *int
+	}{
+		{"1.22.12", RiskLow, intPtr(0)},
+		{"1.22.11", RiskMedium, intPtr(1)},
+		{"1.22.9", RiskMedium, intPtr(3)},
+		{"1.22.8", RiskHigh, intPtr(4)},
+		{"1.22", RiskUnknown, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.installed, func(t *testing.T) {
+			got := Evaluator{}.PatchRisk(tt.installed, cycle, policy.Scope{})
+			if got.Level != tt.wantLevel || got.Latest != "1.22.12" {
+				t.Errorf("PatchRisk() = %s latest %s, want %s latest 1.22.12", got.Level, got.Latest, tt.wantLevel)
+			}
+			if (got.ReleasesBehind == nil) != (tt.wantBehind == nil) ||
+				(got.ReleasesBehind != nil && *got.ReleasesBehind != *tt.wantBehind) {
+				t.Errorf("PatchRisk() behind = %v, want %v", got.ReleasesBehind, tt.wantBehind)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int { return &n }
@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultTimeout is the per-attempt timeout used when none is configured.
+const DefaultTimeout = 15 * time.Second
+
+// Config describes how to reach an upstream API, e.g. a self-hosted mirror
+// behind a proxy that requires authentication.
+type Config struct {
+	BaseURL string            `mapstructure:"base_url"`
+	Headers map[string]string `mapstructure:"headers"`
+	Token   string            `mapstructure:"token"`
+	CAFile  string            `mapstructure:"ca_file"`
+	Proxy   string            `mapstructure:"proxy"`
+
+	// Timeout bounds each attempt of a request; zero means DefaultTimeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Retries is how many times a request failing with a network error,
+	// 429 or 5xx is retried; zero disables retries.
+	Retries int `mapstructure:"retries"`
+	// RetryWait and RetryMaxWait shape the exponential backoff between
+	// retries; zero means DefaultRetryWait and DefaultRetryMaxWait.
+	RetryWait    time.Duration `mapstructure:"retry_wait"`
+	RetryMaxWait time.Duration `mapstructure:"retry_max_wait"`
+
+	// Logf, if set, receives debug messages about retries.
+	Logf func(format string, args ...interface{}) `mapstructure:"-"`
+}
+
+// MaxDuration is the longest a request made by a client built from cfg can
+// take: every attempt timing out, with the longest backoff between them.
+func (cfg Config) MaxDuration() time.Duration {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	maxWait := cfg.RetryMaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultRetryMaxWait
+	}
+	retries := time.Duration(max(cfg.Retries, 0))
+	return (retries+1)*timeout + retries*maxWait
+}
+
+// New builds an HTTP client from cfg. Without a proxy setting the standard
+// HTTP_PROXY / HTTPS_PROXY / NO_PROXY environment variables are honoured.
+// Transient failures are retried with exponential backoff as configured in
+// cfg; the timeout applies to each attempt rather than to the whole request.
+func New(cfg Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CAFile)
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", cfg.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	var rt http.RoundTripper = transport
+	if len(cfg.Headers) > 0 || cfg.Token != "" {
+		ht := &headerTransport{base: transport, headers: cfg.Headers, token: cfg.Token}
+		if cfg.BaseURL != "" {
+			base, err := url.Parse(cfg.BaseURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid base URL %q: %w", cfg.BaseURL, err)
+			}
+			ht.host = base.Host
+		}
+		rt = ht
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	retry := &retryTransport{
+		base:    rt,
+		retries: max(cfg.Retries, 0),
+		wait:    cfg.RetryWait,
+		maxWait: cfg.RetryMaxWait,
+		timeout: timeout,
+		debugf:  cfg.Logf,
+	}
+	if retry.wait <= 0 {
+		retry.wait = DefaultRetryWait
+	}
+	if retry.maxWait <= 0 {
+		retry.maxWait = DefaultRetryMaxWait
+	}
+
+	return &http.Client{Transport: retry}, nil
+}
+
+// headerTransport adds static headers and a bearer token to requests for
+// the configured host. Redirects to other hosts are sent without them, so
+// credentials never leak to a third party.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+	token   string
+	// host is the host of the base URL; when empty, the host of the first
+	// request of a redirect chain is trusted instead.
+	host string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.trustedHost(req) {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// trustedHost returns the host that may receive the headers for req.
+func (t *headerTransport) trustedHost(req *http.Request) string {
+	if t.host != "" {
+		return t.host
+	}
+	for req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+	return req.URL.Host
+}
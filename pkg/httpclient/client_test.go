@@ -0,0 +1,168 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want map[string]string
+	}{
+		{
+			name: "none",
+			cfg:  Config{},
+			want: map[string]string{"Authorization": "", "X-Team": ""},
+		},
+		{
+			name: "token and headers",
+			cfg:  Config{Token: "secret", Headers: map[string]string{"X-Team": "platform"}},
+			want: map[string]string{"Authorization": "Bearer secret", "X-Team": "platform"},
+		},
+		{
+			name: "base URL host",
+			cfg:  Config{BaseURL: server.URL + "/api", Token: "secret"},
+			want: map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			name: "other base URL host",
+			cfg:  Config{BaseURL: "https://mirror.example.com/api", Token: "secret"},
+			want: map[string]string{"Authorization": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			resp.Body.Close()
+			for k, v := range tt.want {
+				if got.Get(k) != v {
+					t.Errorf("header %s = %q, want %q", k, got.Get(k), v)
+				}
+			}
+		})
+	}
+}
+
+func TestNewHeadersNotSentOnCrossHostRedirect(t *testing.T) {
+	var leaked http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Clone()
+	}))
+	defer other.Close()
+
+	var sameHost http.Header
+	mux := http.NewServeMux()
+	mux.HandleFunc("/same", func(w http.ResponseWriter, r *http.Request) {
+		sameHost = r.Header.Clone()
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/same", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, baseURL := range []string{"", server.URL} {
+		leaked, sameHost = nil, nil
+		client, err := New(Config{BaseURL: baseURL, Token: "secret", Headers: map[string]string{"X-Team": "platform"}})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		resp.Body.Close()
+
+		if sameHost.Get("Authorization") != "Bearer secret" {
+			t.Errorf("base URL %q: same-host redirect lost the token", baseURL)
+		}
+		if leaked == nil {
+			t.Fatalf("base URL %q: redirect target not reached", baseURL)
+		}
+		if leaked.Get("Authorization") != "" || leaked.Get("X-Team") != "" {
+			t.Errorf("base URL %q: headers sent to another host: %v", baseURL, leaked)
+		}
+	}
+}
+
+func TestNewTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := New(Config{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	start := time.Now()
+	_, err = client.Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Get took %s, want about the 50ms timeout", elapsed)
+	}
+}
+
+func TestConfigMaxDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want time.Duration
+	}{
+		{"defaults", Config{}, DefaultTimeout},
+		{"single attempt", Config{Timeout: 5 * time.Second}, 5 * time.Second},
+		{"retries", Config{Timeout: 5 * time.Second, Retries: 2, RetryMaxWait: time.Second}, 17 * time.Second},
+		{"default max wait", Config{Timeout: time.Second, Retries: 1}, time.Second + time.Second + DefaultRetryMaxWait},
+		{"negative retries", Config{Timeout: time.Second, Retries: -1}, time.Second},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.MaxDuration(); got != tt.want {
+			t.Errorf("%s: MaxDuration() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"missing CA file", Config{CAFile: "testdata/does-not-exist.pem"}},
+		{"invalid proxy", Config{Proxy: "://proxy"}},
+		{"invalid base URL", Config{BaseURL: "://mirror", Token: "secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("New succeeded, want an error")
+			}
+		})
+	}
+}
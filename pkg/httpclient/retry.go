@@ -0,0 +1,152 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultRetries is how many times a failed request is retried when no
+	// retry count is configured.
+	DefaultRetries = 3
+	// DefaultRetryWait is the base delay before the first retry; it doubles
+	// with every further attempt.
+	DefaultRetryWait = 500 * time.Millisecond
+	// DefaultRetryMaxWait caps a single backoff delay, including delays
+	// requested by the server through Retry-After.
+	DefaultRetryMaxWait = 30 * time.Second
+)
+
+// retryTransport retries idempotent requests that fail with a network error,
+// 429 Too Many Requests or a 5xx status. Each attempt gets its own timeout so
+// a slow first attempt does not use up the budget of the retries.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	wait    time.Duration
+	maxWait time.Duration
+	timeout time.Duration
+	debugf  func(format string, args ...interface{})
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.attempt(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+		if attempt >= t.retries || !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > t.maxWait {
+					// The server asked us to stay away for longer than we
+					// are willing to wait; hand its answer to the caller.
+					return resp, nil
+				}
+				delay = after
+			}
+			t.logf("%s %s returned %s, retrying in %s (attempt %d of %d)", req.Method, req.URL, resp.Status, delay.Round(time.Millisecond), attempt+1, t.retries)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			t.logf("%s %s failed: %v, retrying in %s (attempt %d of %d)", req.Method, req.URL, err, delay.Round(time.Millisecond), attempt+1, t.retries)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt performs a single request bounded by the per-attempt timeout. The
+// timeout stays in force until the response body is closed.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.Clone(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns the delay before retry number attempt+1: exponential
+// growth from the base wait, capped at maxWait, with half of it randomised
+// so parallel scans do not retry in lockstep.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.wait << attempt
+	if d <= 0 || d > t.maxWait {
+		d = t.maxWait
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half)
+}
+
+func (t *retryTransport) logf(format string, args ...interface{}) {
+	if t.debugf != nil {
+		t.debugf(format, args...)
+	}
+}
+
+// retryable reports whether a request that produced resp or err is worth
+// another attempt.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter parses a Retry-After header, which holds either a number of
+// seconds or an HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		d := at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,248 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sequenceServer answers the nth request with statuses[n], repeating the
+// last status, and counts the requests it received.
+func sequenceServer(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		status := statuses[min(n, len(statuses)-1)]
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestClient(t *testing.T, cfg Config) *http.Client {
+	t.Helper()
+	if cfg.RetryWait == 0 {
+		cfg.RetryWait = time.Millisecond
+	}
+	if cfg.RetryMaxWait == 0 {
+		cfg.RetryMaxWait = 5 * time.Millisecond
+	}
+	client, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return client
+}
+
+func TestRetryStatuses(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int
+		retries    int
+		wantStatus int
+		wantCalls  int32
+	}{
+		{"success", http.MethodGet, []int{200}, 3, 200, 1},
+		{"recovers from 503", http.MethodGet, []int{503, 502, 200}, 3, 200, 3},
+		{"recovers from 429", http.MethodGet, []int{429, 200}, 3, 200, 2},
+		{"gives up after retries", http.MethodGet, []int{500}, 2, 500, 3},
+		{"retries disabled", http.MethodGet, []int{503, 200}, 0, 503, 1},
+		{"client error not retried", http.MethodGet, []int{404, 200}, 3, 404, 1},
+		{"HEAD retried", http.MethodHead, []int{503, 200}, 3, 200, 2},
+		{"POST not retried", http.MethodPost, []int{503, 200}, 3, 503, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := sequenceServer(t, tt.statuses, nil)
+			client := newTestClient(t, Config{Retries: tt.retries})
+
+			req, _ := http.NewRequest(tt.method, server.URL, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus || calls.Load() != tt.wantCalls {
+				t.Errorf("status %d after %d call(s), want %d after %d", resp.StatusCode, calls.Load(), tt.wantStatus, tt.wantCalls)
+			}
+			if tt.method == http.MethodGet && string(body) != http.StatusText(tt.wantStatus) {
+				t.Errorf("body = %q, want the body of the last response", body)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHonoured(t *testing.T) {
+	server, calls := sequenceServer(t, []int{429, 200}, http.Header{"Retry-After": {"1"}})
+	client := newTestClient(t, Config{Retries: 3, RetryMaxWait: 5 * time.Second})
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the 1s Retry-After", elapsed)
+	}
+	if resp.StatusCode != 200 || calls.Load() != 2 {
+		t.Errorf("status %d after %d call(s), want 200 after 2", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryAfterAboveMaxWait(t *testing.T) {
+	server, calls := sequenceServer(t, []int{503, 200}, http.Header{"Retry-After": {"3600"}})
+	client := newTestClient(t, Config{Retries: 3, RetryMaxWait: time.Second})
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 503 || calls.Load() != 1 {
+		t.Errorf("status %d after %d call(s), want the 503 without retrying", resp.StatusCode, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %s, want no wait", elapsed)
+	}
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, Config{Retries: 1})
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 2 {
+		t.Errorf("%d call(s), want a retry after the dropped connection", calls.Load())
+	}
+}
+
+func TestRetryAttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, Config{Retries: 1, Timeout: 50 * time.Millisecond})
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "ok" {
+		t.Errorf("body = %q, %v; want the second attempt's response", body, err)
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	server, calls := sequenceServer(t, []int{503}, nil)
+	client := newTestClient(t, Config{Retries: 5, RetryWait: time.Hour, RetryMaxWait: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := client.Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do error = %v, want the context deadline", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d call(s), want 1", calls.Load())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	rt := &retryTransport{wait: 100 * time.Millisecond, maxWait: time.Second}
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{62, time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := rt.backoff(tt.attempt); d < tt.max/2 || d >= tt.max {
+				t.Errorf("backoff(%d) = %s, want within [%s, %s)", tt.attempt, d, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-5", 0, false},
+		{"Sun, 01 Mar 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 01 Mar 2026 11:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("retryAfter(%q) = %s, %v; want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryLogs(t *testing.T) {
+	server, _ := sequenceServer(t, []int{503, 200}, nil)
+	var logs []string
+	client := newTestClient(t, Config{Retries: 1, Logf: func(format string, args ...interface{}) {
+		logs = append(logs, format)
+	}})
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if len(logs) != 1 || !strings.Contains(logs[0], "retrying in") {
+		t.Errorf("logs = %q, want one retry message", logs)
+	}
+}
@@ -0,0 +1,425 @@
+// Package policy defines how remaining support time and version staleness
+// map to risk levels. A policy sets global rules, optional overrides per
+// product, Kubernetes namespace or project path, and the names and order of
+// the risk levels themselves.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Built-in level names.
+const (
+	Critical = "CRITICAL"
+	High     = "HIGH"
+	Medium   = "MEDIUM"
+	Low      = "LOW"
+	Unknown  = "UNKNOWN"
+)
+
+// Threshold assigns Level to anything with at most MaxDays left.
+type Threshold struct {
+	Level   string `json:"level"`
+	MaxDays int    `json:"max_days"`
+}
+
+// DistanceRule assigns Level to a version at least Min releases behind.
+type DistanceRule struct {
+	Level string `json:"level"`
+	Min   int    `json:"min"`
+}
+
+// Staleness rates packages without lifecycle data, such as ArtifactHub
+// charts, by how far the installed version trails the latest one.
+type Staleness struct {
+	Deprecated string         `json:"deprecated,omitempty"`
+	Major      []DistanceRule `json:"major,omitempty"`
+	Minor      []DistanceRule `json:"minor,omitempty"`
+	Current    string         `json:"current,omitempty"`
+}
+
+// Rules is a complete or partial set of rating rules. Empty fields in an
+// override inherit the value of the enclosing rules.
+type Rules struct {
+	// Thresholds are checked in ascending MaxDays order; the first that
+	// covers the remaining days wins, otherwise Default applies.
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+	Default    string      `json:"default,omitempty"`
+	// Patches rate patch staleness within a cycle: the rule with the highest
+	// Min the installed version reaches wins, otherwise PatchesCurrent.
+	Patches        []DistanceRule `json:"patches,omitempty"`
+	PatchesCurrent string         `json:"patches_current,omitempty"`
+	Staleness      *Staleness     `json:"staleness,omitempty"`
+}
+
+// Override replaces parts of the global rules for matching findings. Every
+// non-empty selector must match; selectors are glob patterns where "*"
+// matches within a path segment and "**" across segments.
+type Override struct {
+	Products   []string `json:"products,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Paths      []string `json:"paths,omitempty"`
+	Rules
+}
+
+// Policy is a complete risk policy.
+type Policy struct {
+	// Levels lists the level names from most to least severe.
+	Levels []string `json:"levels,omitempty"`
+	// UnknownLevel is reported when risk cannot be determined.
+	UnknownLevel string `json:"unknown,omitempty"`
+	Rules
+	Overrides []Override `json:"overrides,omitempty"`
+}
+
+// Scope identifies what is being rated, for matching overrides.
+type Scope struct {
+	Product   string
+	Namespace string
+	Path      string
+}
+
+// Default returns the built-in policy: CRITICAL once EOL is reached, HIGH
+// within 90 days, MEDIUM within 180 days and LOW beyond.
+func Default() *Policy {
+	return &Policy{
+		Levels:       []string{Critical, High, Medium, Low},
+		UnknownLevel: Unknown,
+		Rules: Rules{
+			Thresholds: []Threshold{
+				{Level: Critical, MaxDays: 0},
+				{Level: High, MaxDays: 90},
+				{Level: Medium, MaxDays: 180},
+			},
+			Default:        Low,
+			Patches:        []DistanceRule{{Level: High, Min: 4}, {Level: Medium, Min: 1}},
+			PatchesCurrent: Low,
+			Staleness: &Staleness{
+				Deprecated: Critical,
+				Major:      []DistanceRule{{Level: Critical, Min: 2}, {Level: High, Min: 1}},
+				Minor:      []DistanceRule{{Level: High, Min: 3}, {Level: Medium, Min: 1}},
+				Current:    Low,
+			},
+		},
+	}
+}
+
+// Parse builds a policy from its JSON form, filling anything left out with
+// the built-in defaults. When custom level names are given without rules
+// that use them, the built-in rules are mapped onto the custom levels by
+// severity.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return p.complete()
+}
+
+func (p *Policy) complete() (*Policy, error) {
+	def := Default()
+	if len(p.Levels) == 0 {
+		p.Levels = def.Levels
+	}
+	if p.UnknownLevel == "" {
+		p.UnknownLevel = def.UnknownLevel
+	}
+
+	mapped := def.Rules.mapLevels(def.Levels, p.Levels)
+	p.Rules = p.Rules.inherit(mapped)
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// mapLevels renames the levels used by r from the from scale to the to
+// scale by relative severity.
+func (r Rules) mapLevels(from, to []string) Rules {
+	rank := make(map[string]int, len(from))
+	for i, l := range from {
+		rank[l] = i
+	}
+	m := func(level string) string {
+		i, ok := rank[level]
+		if !ok || len(to) == 0 {
+			return level
+		}
+		return scaleRank(i, len(from), to)
+	}
+
+	out := Rules{Default: m(r.Default), PatchesCurrent: m(r.PatchesCurrent)}
+	for _, t := range r.Thresholds {
+		out.Thresholds = append(out.Thresholds, Threshold{Level: m(t.Level), MaxDays: t.MaxDays})
+	}
+	mapRules := func(in []DistanceRule) []DistanceRule {
+		var res []DistanceRule
+		for _, d := range in {
+			res = append(res, DistanceRule{Level: m(d.Level), Min: d.Min})
+		}
+		return res
+	}
+	out.Patches = mapRules(r.Patches)
+	if r.Staleness != nil {
+		out.Staleness = &Staleness{
+			Deprecated: m(r.Staleness.Deprecated),
+			Major:      mapRules(r.Staleness.Major),
+			Minor:      mapRules(r.Staleness.Minor),
+			Current:    m(r.Staleness.Current),
+		}
+	}
+	return out
+}
+
+// scaleRank returns the level of to at the same relative severity as rank
+// i on a scale of n levels.
+func scaleRank(i, n int, to []string) string {
+	if n == 1 {
+		return to[0]
+	}
+	return to[(i*(len(to)-1)+(n-1)/2)/(n-1)]
+}
+
+// MapLevel returns the level of levels nearest to level, one of the
+// built-in levels: the level itself when levels has it, compared
+// case-insensitively, otherwise the level at the same relative severity,
+// as custom levels are mapped onto the built-in rules. It returns "" when
+// levels is empty or level is not a built-in level.
+func MapLevel(level string, levels []string) string {
+	for _, l := range levels {
+		if strings.EqualFold(l, level) {
+			return l
+		}
+	}
+	builtin := Default().Levels
+	for i, l := range builtin {
+		if l == level && len(levels) > 0 {
+			return scaleRank(i, len(builtin), levels)
+		}
+	}
+	return ""
+}
+
+// inherit fills the empty fields of r from parent.
+func (r Rules) inherit(parent Rules) Rules {
+	if len(r.Thresholds) == 0 {
+		r.Thresholds = parent.Thresholds
+	}
+	if r.Default == "" {
+		r.Default = parent.Default
+	}
+	if len(r.Patches) == 0 {
+		r.Patches = parent.Patches
+	}
+	if r.PatchesCurrent == "" {
+		r.PatchesCurrent = parent.PatchesCurrent
+	}
+	switch {
+	case r.Staleness == nil:
+		r.Staleness = parent.Staleness
+	case parent.Staleness != nil:
+		s := *r.Staleness
+		if s.Deprecated == "" {
+			s.Deprecated = parent.Staleness.Deprecated
+		}
+		if len(s.Major) == 0 {
+			s.Major = parent.Staleness.Major
+		}
+		if len(s.Minor) == 0 {
+			s.Minor = parent.Staleness.Minor
+		}
+		if s.Current == "" {
+			s.Current = parent.Staleness.Current
+		}
+		r.Staleness = &s
+	}
+	return r
+}
+
+func (p *Policy) validate() error {
+	known := make(map[string]bool, len(p.Levels))
+	for _, l := range p.Levels {
+		if l == "" {
+			return fmt.Errorf("empty level name")
+		}
+		if known[l] {
+			return fmt.Errorf("level %s is listed twice", l)
+		}
+		known[l] = true
+	}
+
+	check := func(where string, r Rules) error {
+		var levels []string
+		last := -1 << 31
+		for _, t := range r.Thresholds {
+			if t.MaxDays < last {
+				return fmt.Errorf("%s: thresholds must be in ascending max_days order", where)
+			}
+			last = t.MaxDays
+			levels = append(levels, t.Level)
+		}
+		levels = append(levels, r.Default, r.PatchesCurrent)
+		for _, d := range r.Patches {
+			levels = append(levels, d.Level)
+		}
+		if s := r.Staleness; s != nil {
+			levels = append(levels, s.Deprecated, s.Current)
+			for _, d := range append(append([]DistanceRule(nil), s.Major...), s.Minor...) {
+				levels = append(levels, d.Level)
+			}
+		}
+		for _, l := range levels {
+			if l != "" && !known[l] {
+				return fmt.Errorf("%s: level %s is not listed in levels %v", where, l, p.Levels)
+			}
+		}
+		return nil
+	}
+
+	if err := check("policy", p.Rules); err != nil {
+		return err
+	}
+	for i, o := range p.Overrides {
+		if len(o.Products) == 0 && len(o.Namespaces) == 0 && len(o.Paths) == 0 {
+			return fmt.Errorf("override %d has no products, namespaces or paths", i+1)
+		}
+		for _, pattern := range append(append(append([]string(nil), o.Products...), o.Namespaces...), o.Paths...) {
+			if _, err := globRegexp(pattern); err != nil {
+				return fmt.Errorf("override %d: invalid pattern %q: %w", i+1, pattern, err)
+			}
+		}
+		if err := check(fmt.Sprintf("override %d", i+1), o.Rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// For returns the rules that apply to scope: the global rules with every
+// matching override applied in order.
+func (p *Policy) For(scope Scope) Rules {
+	r := p.Rules
+	for _, o := range p.Overrides {
+		if o.matches(scope) {
+			r = o.Rules.inherit(r)
+		}
+	}
+	return r
+}
+
+func (o Override) matches(s Scope) bool {
+	return matchAny(o.Products, s.Product) &&
+		matchAny(o.Namespaces, s.Namespace) &&
+		matchAny(o.Paths, filepath.ToSlash(s.Path))
+}
+
+// Match reports whether value matches the glob pattern, where "*" matches
+// within a path segment, "**" across segments and "?" a single character.
+func Match(pattern, value string) bool {
+	re, err := globRegexp(pattern)
+	return err == nil && re.MatchString(value)
+}
+
+// matchAny reports whether value matches one of patterns; an empty pattern
+// list matches anything.
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if Match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// Rank returns the severity rank of level, 0 being the most severe, or -1
+// for levels outside the policy such as the unknown level.
+func (p *Policy) Rank(level string) int {
+	for i, l := range p.Levels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// ForDays returns the level for something with days left until it loses
+// support.
+func (r Rules) ForDays(days int) string {
+	for _, t := range r.Thresholds {
+		if days <= t.MaxDays {
+			return t.Level
+		}
+	}
+	return r.Default
+}
+
+// ForPatches returns the level for an installed version behind releases
+// behind the latest release of its cycle, as counted by
+// endoflife.ReleasesBehind.
+func (r Rules) ForPatches(behind int) string {
+	return matchDistance(r.Patches, behind, r.PatchesCurrent)
+}
+
+// matchDistance returns the level of the rule with the highest Min that
+// distance reaches, or fallback.
+func matchDistance(rules []DistanceRule, distance int, fallback string) string {
+	best, bestMin := fallback, 0
+	for _, d := range rules {
+		if distance >= d.Min && d.Min > 0 && d.Min >= bestMin {
+			best, bestMin = d.Level, d.Min
+		}
+	}
+	return best
+}
+
+// ForStaleness returns the level for a package majorBehind major releases
+// and minorBehind minor releases behind the latest version.
+func (s Staleness) ForStaleness(deprecated bool, majorBehind, minorBehind int) string {
+	if deprecated {
+		return s.Deprecated
+	}
+	if majorBehind > 0 {
+		if l := matchDistance(s.Major, majorBehind, ""); l != "" {
+			return l
+		}
+	}
+	if minorBehind > 0 {
+		if l := matchDistance(s.Minor, minorBehind, ""); l != "" {
+			return l
+		}
+	}
+	return s.Current
+}
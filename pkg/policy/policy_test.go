@@ -0,0 +1,204 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDefaults(t *testing.T) {
+	p, err := Parse([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(p, Default()) {
+		t.Errorf("Parse({}) = %+v, want the built-in policy", p)
+	}
+}
+
+func TestParseCustomLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []string
+		// want is the mapped CRITICAL, HIGH, MEDIUM and LOW thresholds
+		// and default.
+		want []string
+	}{
+		{"same count", []string{"P1", "P2", "P3", "P4"}, []string{"P1", "P2", "P3", "P4"}},
+		{"fewer levels", []string{"BLOCK", "WARN", "OK"}, []string{"BLOCK", "WARN", "WARN", "OK"}},
+		{"two levels", []string{"FAIL", "PASS"}, []string{"FAIL", "FAIL", "PASS", "PASS"}},
+		{"more levels", []string{"S1", "S2", "S3", "S4", "S5", "S6"}, []string{"S1", "S3", "S4", "S6"}},
+		{"single level", []string{"ANY"}, []string{"ANY", "ANY", "ANY", "ANY"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := `{"levels": ["` + strings.Join(tt.levels, `", "`) + `"]}`
+			p, err := Parse([]byte(data))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got := []string{p.ForDays(0), p.ForDays(90), p.ForDays(180), p.ForDays(181)}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("levels by days = %v, want %v", got, tt.want)
+			}
+			if p.UnknownLevel != Unknown {
+				t.Errorf("unknown level = %q, want %q", p.UnknownLevel, Unknown)
+			}
+			for _, level := range []string{p.ForPatches(0), p.ForPatches(5), p.Staleness.ForStaleness(true, 0, 0)} {
+				if p.Rank(level) < 0 {
+					t.Errorf("built-in rule mapped to %q, outside %v", level, tt.levels)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"levels": ["P1", "P2", "P3", "OK"],
+		"unknown": "P?",
+		"thresholds": [{"level": "P1", "max_days": 0}, {"level": "P2", "max_days": 30}],
+		"default": "OK",
+		"patches": [{"level": "P3", "min": 10}],
+		"patches_current": "OK"
+	}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"EOL", p.ForDays(-5), "P1"},
+		{"30 days", p.ForDays(30), "P2"},
+		{"31 days", p.ForDays(31), "OK"},
+		{"current", p.ForPatches(0), "OK"},
+		{"9 behind", p.ForPatches(9), "OK"},
+		{"10 behind", p.ForPatches(10), "P3"},
+		{"unknown", p.UnknownLevel, "P?"},
+		// Staleness is not set, so the built-in rules are mapped.
+		{"deprecated chart", p.Staleness.ForStaleness(true, 0, 0), "P1"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"thresholds": [{"level": "CRITICAL", "max_days": 0}, {"level": "HIGH", "max_days": 30}],
+		"overrides": [
+			{"products": ["nodejs", "python"], "thresholds": [{"level": "CRITICAL", "max_days": 90}]},
+			{"namespaces": ["kube-*"], "default": "MEDIUM"},
+			{"paths": ["services/**/legacy/*"], "patches": [{"level": "LOW", "min": 1}]},
+			{"products": ["nodejs"], "namespaces": ["prod"], "thresholds": [{"level": "CRITICAL", "max_days": 365}]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		scope Scope
+		days  int
+		want  string
+	}{
+		{"no override", Scope{Product: "go"}, 60, "LOW"},
+		{"no override, threshold", Scope{Product: "go"}, 20, "HIGH"},
+		{"product override", Scope{Product: "nodejs"}, 60, "CRITICAL"},
+		{"product override keeps default", Scope{Product: "python"}, 120, "LOW"},
+		{"namespace glob", Scope{Product: "go", Namespace: "kube-system"}, 60, "MEDIUM"},
+		{"namespace glob, inherited thresholds", Scope{Product: "go", Namespace: "kube-system"}, 20, "HIGH"},
+		{"namespace glob does not match", Scope{Product: "go", Namespace: "kube"}, 60, "LOW"},
+		{"overrides apply in order", Scope{Product: "nodejs", Namespace: "prod"}, 200, "CRITICAL"},
+		{"every selector must match", Scope{Product: "nodejs", Namespace: "staging"}, 200, "LOW"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.For(tt.scope).ForDays(tt.days); got != tt.want {
+				t.Errorf("For(%+v).ForDays(%d) = %s, want %s", tt.scope, tt.days, got, tt.want)
+			}
+		})
+	}
+
+	if got := p.For(Scope{Path: "services/api/legacy/go.mod"}).ForPatches(1); got != "LOW" {
+		t.Errorf("path override: ForPatches(1) = %s, want LOW", got)
+	}
+	if got := p.For(Scope{Path: "services/legacy/go.mod"}).ForPatches(1); got != "MEDIUM" {
+		t.Errorf("unmatched path: ForPatches(1) = %s, want MEDIUM", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"invalid JSON", `{"levels": [}`, "invalid character"},
+		{"duplicate level", `{"levels": ["A", "A"]}`, "listed twice"},
+		{"empty level", `{"levels": ["A", ""]}`, "empty level"},
+		{"unlisted level", `{"levels": ["A", "B"], "default": "C"}`, "level C is not listed"},
+		{"unordered thresholds", `{"thresholds": [{"level": "HIGH", "max_days": 30}, {"level": "CRITICAL", "max_days": 0}]}`, "ascending"},
+		{"override without selector", `{"overrides": [{"default": "LOW"}]}`, "no products, namespaces or paths"},
+		{"override with unlisted level", `{"overrides": [{"products": ["go"], "default": "P9"}]}`, "override 1: level P9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"nodejs", "nodejs", true},
+		{"node*", "nodejs", true},
+		{"kube-*", "kube-system", true},
+		{"apps/*/go.mod", "apps/api/go.mod", true},
+		{"apps/*/go.mod", "apps/api/v2/go.mod", false},
+		{"apps/**/go.mod", "apps/api/v2/go.mod", true},
+		{"python3.1?", "python3.12", true},
+		{"a.b", "axb", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMapLevel(t *testing.T) {
+	tests := []struct {
+		level  string
+		levels []string
+		want   string
+	}{
+		{High, []string{Critical, High, Medium, Low}, High},
+		{High, []string{"critical", "high", "low"}, "high"},
+		{High, []string{"EOL", "SOON", "OK"}, "SOON"},
+		{High, []string{"BAD", "OK"}, "BAD"},
+		{High, []string{"P0", "P1", "P2", "P3", "P4"}, "P1"},
+		{Critical, []string{"P0", "P1", "P2", "P3", "P4"}, "P0"},
+		{Low, []string{"EOL", "SOON", "OK"}, "OK"},
+		{High, []string{"ONLY"}, "ONLY"},
+		{High, nil, ""},
+		{"SEVERE", []string{"EOL", "SOON", "OK"}, ""},
+	}
+	for _, tt := range tests {
+		if got := MapLevel(tt.level, tt.levels); got != tt.want {
+			t.Errorf("MapLevel(%s, %v) = %q, want %q", tt.level, tt.levels, got, tt.want)
+		}
+	}
+}
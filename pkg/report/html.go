@@ -0,0 +1,214 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+)
+
+// HTML is the self-contained HTML report output format.
+const HTML = "html"
+
+type htmlReport struct {
+	Title       string
+	Generated   string
+	Version     string
+	Total       int
+	Counts      []htmlCount
+	Headers     []string
+	Groups      []htmlGroup
+	ShowGroups  bool
+	Narrative   string
+	Suggestions string
+	// Listing hides the summary of a report that lists something other
+	// than findings.
+	Listing bool
+}
+
+type htmlCount struct {
+	Level string
+	Count int
+	Band  string
+}
+
+type htmlGroup struct {
+	Name string
+	Rows [][]htmlCell
+}
+
+type htmlCell struct {
+	Text string
+	// Band colours risk level cells.
+	Band string
+	// Sort overrides the text as sort key, so levels sort by severity.
+	Sort string
+	// Title is shown on hover, the waiver of accepted risks.
+	Title string
+}
+
+// renderHTML writes a single HTML page without external resources: summary
+// counts per risk level, the findings grouped per manifest, namespace or
+// product in tables sortable by any column, and any AI reports. The rows of
+// a listing are written as one sortable table.
+func renderHTML(w io.Writer, r *Report) error {
+	data := htmlReport{
+		Title:       "eolctl " + r.Title(),
+		Version:     r.Version,
+		Total:       len(r.Findings),
+		Narrative:   r.Narrative,
+		Suggestions: r.Suggestions,
+	}
+	if !r.GeneratedAt.IsZero() {
+		data.Generated = r.GeneratedAt.UTC().Format("2006-01-02 15:04 MST")
+	}
+	for _, c := range r.counts() {
+		data.Counts = append(data.Counts, htmlCount{Level: c.Level, Count: c.Count, Band: r.band(c.Level)})
+	}
+
+	if r.Rows != nil {
+		data.Listing = true
+		if !r.NoHeaders {
+			data.Headers = r.Rows.Header
+		}
+		if len(r.Rows.Data) > 0 {
+			group := htmlGroup{}
+			for _, row := range r.Rows.Data {
+				cells := make([]htmlCell, len(row))
+				for i, text := range row {
+					cells[i] = htmlCell{Text: text}
+				}
+				group.Rows = append(group.Rows, cells)
+			}
+			data.Groups = []htmlGroup{group}
+		}
+		return htmlTemplate.Execute(w, data)
+	}
+
+	columns := r.columns()
+	for _, c := range columns {
+		data.Headers = append(data.Headers, c.header)
+	}
+	index := map[string]int{}
+	for _, f := range r.Findings {
+		name := f.groupName()
+		i, ok := index[name]
+		if !ok {
+			i = len(data.Groups)
+			index[name] = i
+			data.Groups = append(data.Groups, htmlGroup{Name: name})
+		}
+		row := make([]htmlCell, len(columns))
+		for j, c := range columns {
+			cell := htmlCell{Text: c.value(f)}
+			if c.level && cell.Text != "" {
+				cell.Band = r.band(cell.Text)
+				cell.Sort = strconv.Itoa(r.sortRank(cell.Text))
+				if f.Waiver != nil && cell.Text == f.Risk {
+					cell.Title = fmt.Sprintf("%s accepted: %s (owner %s, expires %s)", f.AcceptedRisk, f.Waiver.Reason, f.Waiver.Owner, f.Waiver.Expires)
+				}
+			}
+			row[j] = cell
+		}
+		data.Groups[i].Rows = append(data.Groups[i].Rows, row)
+	}
+	data.ShowGroups = len(data.Groups) > 1 || r.Source != SourceProduct
+
+	return htmlTemplate.Execute(w, data)
+}
+
+// sortRank orders levels by severity, then accepted risks, then anything
+// outside the policy.
+func (r *Report) sortRank(level string) int {
+	switch r.band(level) {
+	case bandAccepted:
+		return len(r.Levels)
+	case bandNone:
+		return len(r.Levels) + 1
+	default:
+		return r.Rank(level)
+	}
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { margin-bottom: 0.2rem; }
+.meta { color: #656d76; margin-top: 0; }
+.summary { display: flex; flex-wrap: wrap; gap: 0.75rem; margin: 1.5rem 0; }
+.card { border: 1px solid #d0d7de; border-radius: 6px; padding: 0.6rem 1rem; min-width: 6rem; }
+.card .count { font-size: 1.6rem; font-weight: 600; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; font-size: 0.9rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.35rem 0.6rem; text-align: left; white-space: nowrap; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; }
+th[data-dir="asc"]::after { content: " \25B2"; }
+th[data-dir="desc"]::after { content: " \25BC"; }
+.band-critical { background: #b60205; color: #fff; font-weight: 600; }
+.band-high { background: #e99695; }
+.band-medium { background: #fbca04; }
+.band-low { background: #c2e0c6; }
+.band-accepted { background: #bfdadc; }
+.band-none { background: #eaeef2; }
+.ai { white-space: pre-wrap; background: #f6f8fa; border: 1px solid #d0d7de; border-radius: 6px; padding: 1rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{if .Generated}}Generated {{.Generated}}{{end}}{{if .Version}} by eolctl {{.Version}}{{end}}</p>
+
+{{if not .Listing}}<div class="summary">
+<div class="card"><div class="count">{{.Total}}</div>component(s)</div>
+{{- range .Counts}}
+<div class="card band-{{.Band}}"><div class="count">{{.Count}}</div>{{.Level}}</div>
+{{- end}}
+</div>{{end}}
+
+{{if not .Groups}}<p>{{if .Listing}}No entries.{{else}}No findings.{{end}}</p>{{end}}
+{{- range .Groups}}
+{{if $.ShowGroups}}<h2>{{.Name}}</h2>{{end}}
+<table class="findings">
+{{if $.Headers}}<thead><tr>{{range $.Headers}}<th>{{.}}</th>{{end}}</tr></thead>{{end}}
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td{{if .Band}} class="band-{{.Band}}"{{end}}{{if .Sort}} data-sort="{{.Sort}}"{{end}}{{if .Title}} title="{{.Title}}"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+
+{{if .Narrative}}<h2>AI risk summary</h2>
+<div class="ai">{{.Narrative}}</div>{{end}}
+{{if .Suggestions}}<h2>AI upgrade suggestions</h2>
+<div class="ai">{{.Suggestions}}</div>{{end}}
+
+<script>
+document.querySelectorAll("table.findings th").forEach(function (th) {
+  th.addEventListener("click", function () {
+    var table = th.closest("table"), body = table.tBodies[0], col = th.cellIndex;
+    var dir = th.dataset.dir === "asc" ? "desc" : "asc";
+    table.querySelectorAll("th").forEach(function (h) { delete h.dataset.dir; });
+    th.dataset.dir = dir;
+    var key = function (row) {
+      var cell = row.cells[col];
+      return cell.dataset.sort !== undefined ? cell.dataset.sort : cell.textContent;
+    };
+    var rows = Array.prototype.slice.call(body.rows);
+    rows.sort(function (a, b) {
+      var x = key(a), y = key(b), nx = parseFloat(x), ny = parseFloat(y);
+      var cmp = (!isNaN(nx) && !isNaN(ny) && String(nx) === x && String(ny) === y)
+        ? nx - ny
+        : x.localeCompare(y, undefined, { numeric: true });
+      return dir === "asc" ? cmp : -cmp;
+    });
+    rows.forEach(function (row) { body.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
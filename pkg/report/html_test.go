@@ -0,0 +1,132 @@
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+func renderString(t *testing.T, format string, r *Report) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Render(&buf, format, r); err != nil {
+		t.Fatalf("Render(%s): %v", format, err)
+	}
+	return buf.String()
+}
+
+func clusterReport() *Report {
+	return &Report{
+		Source:      SourceCluster,
+		Version:     "1.2.3",
+		Levels:      []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"},
+		GeneratedAt: time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+		Findings: []Finding{
+			{Source: SourceCluster, Location: "ingress/ingress-nginx", Namespace: "ingress", Release: "ingress-nginx", Product: "nginx", Version: "1.25.3", EOL: "2024-05-29", Risk: "CRITICAL"},
+			{Source: SourceCluster, Location: "monitoring/prometheus", Namespace: "monitoring", Release: "prometheus", Product: "prometheus", Version: "2.45.0", EOL: "2025-07-31", Risk: "MEDIUM"},
+			{Source: SourceCluster, Location: "ingress/cert-manager", Namespace: "ingress", Release: "cert-manager", Product: "cert-manager", Version: "1.12.3", EOL: "2024-05-31", Risk: waiver.Accepted, AcceptedRisk: "HIGH",
+				Waiver: &waiver.Waiver{Reason: "upgrade <blocked> by CRDs", Owner: "platform", Expires: "2024-12-31"}},
+		},
+		Narrative:   "Upgrade <script>alert('nginx')</script> & cert-manager first.",
+		Suggestions: "Move to <b>nginx 1.27</b>.",
+	}
+}
+
+func TestRenderHTMLSelfContained(t *testing.T) {
+	out := renderString(t, HTML, clusterReport())
+
+	// Nothing may be loaded from elsewhere: no stylesheets, scripts, images
+	// or fonts by URL, so the page renders offline and as a CI artifact.
+	for _, pattern := range []string{
+		`(?i)<link\b`,
+		`(?i)<script[^>]+src=`,
+		`(?i)<img\b`,
+		`(?i)@import`,
+		`(?i)url\(`,
+		`(?i)https?://`,
+		`shields\.io`,
+	} {
+		if loc := regexp.MustCompile(pattern).FindStringIndex(out); loc != nil {
+			t.Errorf("html references an external resource (%s): %q", pattern, out[loc[0]:min(loc[1]+40, len(out))])
+		}
+	}
+	for _, want := range []string{"<style>", "<script>", "</html>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html lacks inline %s", want)
+		}
+	}
+}
+
+func TestRenderHTMLGroups(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     *Report
+		wantGroups []string
+	}{
+		{
+			name:       "cluster by namespace",
+			report:     clusterReport(),
+			wantGroups: []string{"ingress", "monitoring"},
+		},
+		{
+			name: "project by manifest path",
+			report: &Report{Source: SourceProject, Levels: []string{"HIGH", "LOW"}, Findings: []Finding{
+				{Source: SourceProject, Location: "services/api/go.mod", Product: "go", Version: "1.22.5", Risk: "HIGH"},
+				{Source: SourceProject, Location: "web/package.json", Product: "nodejs", Version: "20.11.1", Risk: "LOW"},
+				{Source: SourceProject, Location: "services/api/go.mod", Product: "go", Version: "1.21.0", Risk: "HIGH"},
+			}},
+			wantGroups: []string{"services/api/go.mod", "web/package.json"},
+		},
+		{
+			name: "single product without a heading",
+			report: &Report{Source: SourceProduct, Levels: []string{"HIGH", "LOW"}, Findings: []Finding{
+				{Source: SourceProduct, Product: "go", Cycle: "1.23", Risk: "LOW"},
+				{Source: SourceProduct, Product: "go", Cycle: "1.22", Risk: "HIGH"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := renderString(t, HTML, tt.report)
+			var groups []string
+			for _, m := range regexp.MustCompile(`<h2>([^<]*)</h2>\s*<table`).FindAllStringSubmatch(out, -1) {
+				groups = append(groups, m[1])
+			}
+			if strings.Join(groups, ",") != strings.Join(tt.wantGroups, ",") {
+				t.Errorf("groups = %q, want %q", groups, tt.wantGroups)
+			}
+			if n := strings.Count(out, `<table class="findings">`); n != max(len(tt.wantGroups), 1) {
+				t.Errorf("%d tables, want one per group", n)
+			}
+		})
+	}
+
+	// The ingress group holds both of its releases, in order.
+	out := renderString(t, HTML, clusterReport())
+	ingress := out[strings.Index(out, "<h2>ingress</h2>"):strings.Index(out, "<h2>monitoring</h2>")]
+	if !strings.Contains(ingress, "ingress-nginx") || !strings.Contains(ingress, "cert-manager") || strings.Contains(ingress, "prometheus") {
+		t.Errorf("ingress group holds the wrong releases:\n%s", ingress)
+	}
+}
+
+func TestRenderHTMLEscapes(t *testing.T) {
+	out := renderString(t, HTML, clusterReport())
+	for _, raw := range []string{"<script>alert", "<b>nginx", "upgrade <blocked>"} {
+		if strings.Contains(out, raw) {
+			t.Errorf("html contains unescaped %q", raw)
+		}
+	}
+	for _, want := range []string{
+		"Upgrade &lt;script&gt;alert(&#39;nginx&#39;)&lt;/script&gt; &amp; cert-manager first.",
+		"Move to &lt;b&gt;nginx 1.27&lt;/b&gt;.",
+		`title="HIGH accepted: upgrade &lt;blocked&gt; by CRDs (owner platform, expires 2024-12-31)"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html lacks escaped %q", want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICS is the iCalendar output format.
+const ICS = "ics"
+
+// DefaultReminders are the alarms, in days before each event, used when a
+// report sets none.
+var DefaultReminders = []int{30, 7}
+
+// calendarEvent is a lifecycle date of a finding.
+type calendarEvent struct {
+	kind    string
+	summary string
+	date    time.Time
+}
+
+// events returns the EOL, support end and extended support end dates of f.
+func (f Finding) events() []calendarEvent {
+	var out []calendarEvent
+	add := func(kind, value, what string) {
+		if d, err := time.Parse(time.DateOnly, value); err == nil {
+			out = append(out, calendarEvent{kind: kind, summary: fmt.Sprintf("%s %s", f.Name(), what), date: d})
+		}
+	}
+	add("support", f.Support, "active support ends")
+	add("eol", f.EOL, "reaches end of life")
+	add("extended-support", f.ExtendedSupport, "extended support ends")
+	return out
+}
+
+// uid identifies an event independently of the installed version and the
+// current risk, so re-importing an updated calendar replaces events
+// instead of duplicating them.
+func (f Finding) uid(kind string) string {
+	return f.fingerprint(kind) + "@eolctl"
+}
+
+// renderICS writes one all-day VEVENT per lifecycle date with a display
+// alarm for each reminder.
+func renderICS(w io.Writer, r *Report) error {
+	reminders := r.Reminders
+	if reminders == nil {
+		reminders = DefaultReminders
+	}
+	stamp := r.GeneratedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	cw := &calendarWriter{w: w}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//eolctl//EOL calendar//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	cw.line("X-WR-CALNAME:" + escapeText("eolctl EOL dates"))
+
+	for _, f := range r.Findings {
+		for _, e := range f.events() {
+			cw.line("BEGIN:VEVENT")
+			cw.line("UID:" + f.uid(e.kind))
+			cw.line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+			cw.line("DTSTART;VALUE=DATE:" + e.date.Format("20060102"))
+			cw.line("DTEND;VALUE=DATE:" + e.date.AddDate(0, 0, 1).Format("20060102"))
+			cw.line("SUMMARY:" + escapeText(e.summary))
+			cw.line("DESCRIPTION:" + escapeText(f.description()))
+			cw.line("CATEGORIES:" + strings.ToUpper(e.kind))
+			cw.line("TRANSP:TRANSPARENT")
+			for _, days := range reminders {
+				if days < 0 {
+					continue
+				}
+				cw.line("BEGIN:VALARM")
+				cw.line("ACTION:DISPLAY")
+				cw.line("DESCRIPTION:" + escapeText(e.summary+" "+reminderWhen(days)))
+				cw.line("TRIGGER:" + reminderTrigger(days))
+				cw.line("END:VALARM")
+			}
+			cw.line("END:VEVENT")
+		}
+	}
+	cw.line("END:VCALENDAR")
+	return cw.err
+}
+
+// reminderWhen says when an event is, seen from an alarm days before it.
+func reminderWhen(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
+
+// reminderTrigger returns the TRIGGER duration of an alarm days before an
+// event; an alarm on the day itself fires at its start.
+func reminderTrigger(days int) string {
+	if days == 0 {
+		return "PT0S"
+	}
+	return fmt.Sprintf("-P%dD", days)
+}
+
+// description summarises f for an event body.
+func (f Finding) description() string {
+	var lines []string
+	add := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	add("Product", f.Product)
+	add("Cycle", f.Cycle)
+	add("Installed version", f.Version)
+	add("Latest release", f.Latest)
+	add("Location", f.Location)
+	add("Risk", f.Risk)
+	add("Support phase", f.SupportPhase)
+	if f.Waiver != nil {
+		add("Waiver", fmt.Sprintf("%s (owner %s, expires %s)", f.Waiver.Reason, f.Waiver.Owner, f.Waiver.Expires))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// calendarWriter writes content lines with CRLF endings, folded at 75
+// octets as RFC 5545 requires.
+type calendarWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *calendarWriter) line(s string) {
+	if c.err != nil {
+		return
+	}
+	var sb strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			sb.WriteString("\r\n ")
+			n = 1
+		}
+		sb.WriteRune(r)
+		n += size
+	}
+	sb.WriteString("\r\n")
+	_, c.err = io.WriteString(c.w, sb.String())
+}
+
+// escapeText escapes a TEXT value.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,192 @@
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func renderCalendar(t *testing.T, r *Report) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Render(&buf, ICS, r); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	return buf.String()
+}
+
+// unfold joins folded content lines back into their logical lines.
+func unfold(s string) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func TestCalendarWriterFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:go 1.22 reaches end of life"},
+		{"exactly 75 octets", "DESCRIPTION:" + strings.Repeat("x", 63)},
+		{"long ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multi-byte runes are not split", "SUMMARY:" + strings.Repeat("é", 40) + strings.Repeat("€", 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cw := &calendarWriter{w: &buf}
+			cw.line(tt.line)
+			if cw.err != nil {
+				t.Fatal(cw.err)
+			}
+			out := buf.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Errorf("line does not end with CRLF: %q", out)
+			}
+			for i, physical := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+				if len(physical) > 75 {
+					t.Errorf("physical line %d is %d octets, want at most 75: %q", i, len(physical), physical)
+				}
+				if i > 0 && !strings.HasPrefix(physical, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, physical)
+				}
+				if !utf8.ValidString(physical) {
+					t.Errorf("physical line %d splits a UTF-8 sequence: %q", i, physical)
+				}
+			}
+			if got := unfold(out); len(got) != 1 || got[0] != tt.line {
+				t.Errorf("unfolded = %q, want %q", got, tt.line)
+			}
+			if wantFolded := len(tt.line) > 75; strings.Contains(out, "\r\n ") != wantFolded {
+				t.Errorf("folded = %v, want %v", !wantFolded, wantFolded)
+			}
+		})
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a, b; c", `a\, b\; c`},
+		{`C:\path`, `C:\\path`},
+		{"line one\nline two", `line one\nline two`},
+		{`\,`, `\\\,`},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.in); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderICSReminders(t *testing.T) {
+	tests := []struct {
+		name      string
+		reminders []int
+		want      []string
+	}{
+		{
+			name: "default",
+			want: []string{"go 1.22 reaches end of life in 30 days", "TRIGGER:-P30D", "go 1.22 reaches end of life in 7 days", "TRIGGER:-P7D"},
+		},
+		{
+			name:      "on the day and the day before",
+			reminders: []int{0, 1},
+			want:      []string{"go 1.22 reaches end of life today", "TRIGGER:PT0S", "go 1.22 reaches end of life tomorrow", "TRIGGER:-P1D"},
+		},
+		{
+			name:      "negative reminders are skipped",
+			reminders: []int{-3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := renderCalendar(t, &Report{
+				Reminders: tt.reminders,
+				Findings:  []Finding{{Source: SourceProduct, Product: "go", Cycle: "1.22", EOL: "2025-02-11"}},
+			})
+			var alarms []string
+			for _, line := range unfold(out) {
+				if strings.HasPrefix(line, "TRIGGER:") || (strings.HasPrefix(line, "DESCRIPTION:") && !strings.Contains(line, `\n`) && strings.Contains(line, "end of life ")) {
+					alarms = append(alarms, strings.TrimPrefix(line, "DESCRIPTION:"))
+				}
+			}
+			if strings.Join(alarms, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("alarms = %q, want %q", alarms, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderICSEvents(t *testing.T) {
+	findings := []Finding{{
+		Source:          SourceProject,
+		Location:        "services/api/go.mod",
+		Product:         "go",
+		Cycle:           "1.22",
+		Version:         "1.22.5",
+		Risk:            "HIGH",
+		Support:         "2024-08-13",
+		EOL:             "2025-02-11",
+		ExtendedSupport: "true",
+	}}
+	out := renderCalendar(t, &Report{
+		GeneratedAt: time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+		Reminders:   []int{},
+		Findings:    findings,
+	})
+	lines := unfold(out)
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Errorf("calendar not wrapped in VCALENDAR: %q ... %q", lines[0], lines[len(lines)-1])
+	}
+	for _, want := range []string{
+		"DTSTAMP:20241001T120000Z",
+		"DTSTART;VALUE=DATE:20250211",
+		"DTEND;VALUE=DATE:20250212",
+		"SUMMARY:go 1.22.5 reaches end of life",
+		"SUMMARY:go 1.22.5 active support ends",
+		`DESCRIPTION:Product: go\nCycle: 1.22\nInstalled version: 1.22.5\nLocation: services/api/go.mod\nRisk: HIGH`,
+		"CATEGORIES:EOL",
+	} {
+		if !strings.Contains(strings.Join(lines, "\n"), want) {
+			t.Errorf("calendar lacks %q", want)
+		}
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("%d events, want 2: extended support has no date", n)
+	}
+	if strings.Contains(out, "BEGIN:VALARM") {
+		t.Error("alarms written with no reminders")
+	}
+}
+
+func TestRenderICSStableUIDs(t *testing.T) {
+	uids := func(f Finding, generated time.Time) []string {
+		out := renderCalendar(t, &Report{GeneratedAt: generated, Findings: []Finding{f}})
+		return regexp.MustCompile(`(?m)^UID:(.*)\r$`).FindAllString(out, -1)
+	}
+	f := Finding{Source: SourceProject, Location: "go.mod", Product: "go", Cycle: "1.22", Version: "1.22.5", Risk: "HIGH", EOL: "2025-02-11", Support: "2024-08-13"}
+
+	first := uids(f, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC))
+	if len(first) != 2 || first[0] == first[1] {
+		t.Fatalf("UIDs = %q, want two distinct UIDs", first)
+	}
+
+	// A later run, after a patch upgrade that lowered the risk and moved
+	// the EOL date, updates the same events.
+	upgraded := f
+	upgraded.Version, upgraded.Risk, upgraded.EOL = "1.22.8", "MEDIUM", "2025-03-01"
+	if again := uids(upgraded, time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)); strings.Join(again, ",") != strings.Join(first, ",") {
+		t.Errorf("UIDs changed across runs: %q, then %q", first, again)
+	}
+
+	moved := f
+	moved.Location = "tools/go.mod"
+	if other := uids(moved, time.Time{}); other[0] == first[0] {
+		t.Errorf("the same cycle in another manifest reuses UID %q", first[0])
+	}
+}
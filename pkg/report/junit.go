@@ -0,0 +1,165 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/policy"
+)
+
+// JUnit is the JUnit XML output format, for CI test report views.
+const JUnit = "junit"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// renderJUnit writes one testcase per finding, grouped into a testsuite per
+// manifest, namespace or product. A testcase fails when its risk is at
+// r.FailLevel or more severe, by default HIGH or, with custom levels, the
+// level nearest to it; waived findings are skipped.
+func renderJUnit(w io.Writer, r *Report) error {
+	failLevel := r.FailLevel
+	if failLevel == "" {
+		failLevel = policy.MapLevel(policy.High, r.Levels)
+	}
+	failRank := r.Rank(failLevel)
+
+	var timestamp string
+	if !r.GeneratedAt.IsZero() {
+		timestamp = r.GeneratedAt.UTC().Format(time.RFC3339)
+	}
+
+	out := junitTestSuites{Name: "eolctl"}
+	index := map[string]int{}
+	for _, f := range r.Findings {
+		name := f.groupName()
+		i, ok := index[name]
+		if !ok {
+			i = len(out.Suites)
+			index[name] = i
+			out.Suites = append(out.Suites, junitTestSuite{
+				Name:      name,
+				Time:      "0",
+				Timestamp: timestamp,
+				Properties: []junitProperty{
+					{Name: "eolctl.version", Value: r.Version},
+					{Name: "eolctl.fail_level", Value: failLevel},
+				},
+			})
+		}
+		suite := &out.Suites[i]
+
+		tc := junitTestCase{
+			Name:      f.caseName(),
+			ClassName: "eolctl." + f.Source + "." + strings.ReplaceAll(name, ".", "_"),
+			Time:      "0",
+			SystemOut: &junitText{Text: f.description()},
+		}
+		rank := r.Rank(f.Risk)
+		switch {
+		case f.Waiver != nil:
+			tc.Skipped = &junitSkipped{Message: f.summary(f.AcceptedRisk)}
+			suite.Skipped++
+		case rank >= 0 && failRank >= 0 && rank <= failRank:
+			message := f.summary(f.Risk)
+			tc.Failure = &junitFailure{
+				Message: message,
+				Type:    f.Risk,
+				Text:    message + "\n\n" + f.description(),
+			}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+	for _, s := range out.Suites {
+		out.Tests += s.Tests
+		out.Failures += s.Failures
+		out.Skipped += s.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// groupName groups findings: by manifest for projects, by namespace for
+// clusters and by product otherwise.
+func (f Finding) groupName() string {
+	switch {
+	case f.Source == SourceCluster && f.Namespace != "":
+		return f.Namespace
+	case f.Source == SourceProject && f.Location != "":
+		return f.Location
+	default:
+		return f.Product
+	}
+}
+
+// caseName names a finding's testcase, such as "nodejs 20.11.1" or, in a
+// cluster, "ingress-nginx: nginx 1.25.3".
+func (f Finding) caseName() string {
+	name := f.Product
+	if v := f.DisplayVersion(); v != "" {
+		name += " " + v
+	}
+	if f.Release != "" {
+		name = f.Release + ": " + name
+	}
+	return name
+}
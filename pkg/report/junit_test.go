@@ -0,0 +1,198 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+type junitResult struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Skipped  int `xml:"skipped,attr"`
+	Suites   []struct {
+		Name       string `xml:"name,attr"`
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"properties>property"`
+		Cases []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Message string `xml:"message,attr"`
+				Type    string `xml:"type,attr"`
+			} `xml:"failure"`
+			Skipped *struct {
+				Message string `xml:"message,attr"`
+			} `xml:"skipped"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func renderJUnitResult(t *testing.T, r *Report) junitResult {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Render(&buf, JUnit, r); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var res junitResult
+	if err := xml.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("output is not XML: %v\n%s", err, buf.String())
+	}
+	return res
+}
+
+// outcomes maps each testcase to "fail", "skip" or "pass".
+func (res junitResult) outcomes() map[string]string {
+	out := map[string]string{}
+	for _, s := range res.Suites {
+		for _, c := range s.Cases {
+			switch {
+			case c.Failure != nil:
+				out[c.Name] = "fail"
+			case c.Skipped != nil:
+				out[c.Name] = "skip"
+			default:
+				out[c.Name] = "pass"
+			}
+		}
+	}
+	return out
+}
+
+func TestRenderJUnitFailLevel(t *testing.T) {
+	defaultLevels := []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+	findings := func(levels ...string) []Finding {
+		var out []Finding
+		for _, level := range levels {
+			out = append(out, Finding{Source: SourceProduct, Product: "go", Cycle: level, Risk: level})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		levels    []string
+		failLevel string
+		findings  []Finding
+		wantLevel string
+		want      map[string]string
+	}{
+		{
+			name:      "default fails at HIGH",
+			levels:    defaultLevels,
+			findings:  findings("CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"),
+			wantLevel: "HIGH",
+			want:      map[string]string{"go CRITICAL": "fail", "go HIGH": "fail", "go MEDIUM": "pass", "go LOW": "pass", "go UNKNOWN": "pass"},
+		},
+		{
+			name:      "explicit fail level",
+			levels:    defaultLevels,
+			failLevel: "MEDIUM",
+			findings:  findings("CRITICAL", "MEDIUM", "LOW"),
+			wantLevel: "MEDIUM",
+			want:      map[string]string{"go CRITICAL": "fail", "go MEDIUM": "fail", "go LOW": "pass"},
+		},
+		{
+			name:      "custom levels default to the level nearest HIGH",
+			levels:    []string{"EOL", "SOON", "OK"},
+			findings:  findings("EOL", "SOON", "OK"),
+			wantLevel: "SOON",
+			want:      map[string]string{"go EOL": "fail", "go SOON": "fail", "go OK": "pass"},
+		},
+		{
+			name:      "many custom levels",
+			levels:    []string{"P0", "P1", "P2", "P3", "P4", "P5"},
+			findings:  findings("P0", "P2", "P3", "P5"),
+			wantLevel: "P2",
+			want:      map[string]string{"go P0": "fail", "go P2": "fail", "go P3": "pass", "go P5": "pass"},
+		},
+		{
+			name:      "custom levels keep HIGH when they have it",
+			levels:    []string{"SEVERE", "CRITICAL", "HIGH", "LOW"},
+			findings:  findings("CRITICAL", "HIGH", "LOW"),
+			wantLevel: "HIGH",
+			want:      map[string]string{"go CRITICAL": "fail", "go HIGH": "fail", "go LOW": "pass"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := renderJUnitResult(t, &Report{Source: SourceProduct, Levels: tt.levels, FailLevel: tt.failLevel, Findings: tt.findings})
+			got := res.outcomes()
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s: %s, want %s", name, got[name], want)
+				}
+			}
+			var level string
+			for _, p := range res.Suites[0].Properties {
+				if p.Name == "eolctl.fail_level" {
+					level = p.Value
+				}
+			}
+			if level != tt.wantLevel {
+				t.Errorf("eolctl.fail_level = %q, want %q", level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestRenderJUnitCases(t *testing.T) {
+	res := renderJUnitResult(t, &Report{
+		Source: SourceProject,
+		Levels: []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"},
+		Findings: []Finding{
+			{Source: SourceProject, Location: "go.mod", Product: "go", Cycle: "1.21", Version: "1.21.13", EOL: "2024-08-13", EOLDate: "2024-08-13", DaysUntilEOL: days(-49), Risk: "CRITICAL"},
+			{Source: SourceProject, Location: "web/package.json", Product: "nodejs", Cycle: "20", Version: "20.11.1", Latest: "20.18.0", ReleasesBehind: days(7), EOL: "2026-04-30", EOLDate: "2026-04-30", DaysUntilEOL: days(90), Risk: "HIGH"},
+			{Source: SourceProject, Location: "web/package.json", Product: "nodejs", Cycle: "18", EOL: "true", Risk: "HIGH"},
+			{Source: SourceProject, Location: "rhel.txt", Product: "rhel", Cycle: "8", EOL: "2029-05-31", ExtendedSupport: "2032-05-31", EOLDate: "2032-05-31", DaysUntilEOL: days(2000), Risk: "HIGH"},
+			{Source: SourceProject, Location: "requirements.txt", Product: "python", Cycle: "3.8", EOL: "2024-10-07", EOLDate: "2024-10-07", DaysUntilEOL: days(-3), Risk: waiver.Accepted, AcceptedRisk: "CRITICAL",
+				Waiver: &waiver.Waiver{Reason: "legacy batch job", Owner: "data", Expires: "2025-01-31"}},
+			{Source: SourceProject, Location: "requirements.txt", Product: "flask", Cycle: "3.0", Risk: "LOW"},
+		},
+	})
+
+	if res.Tests != 6 || res.Failures != 4 || res.Skipped != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d skipped; want 6, 4, 1", res.Tests, res.Failures, res.Skipped)
+	}
+	var suites []string
+	for _, s := range res.Suites {
+		suites = append(suites, s.Name)
+	}
+	if got := strings.Join(suites, ", "); got != "go.mod, web/package.json, rhel.txt, requirements.txt" {
+		t.Errorf("suites = %s, want one per manifest in order of appearance", got)
+	}
+
+	messages := map[string]string{}
+	types := map[string]string{}
+	for _, s := range res.Suites {
+		for _, c := range s.Cases {
+			switch {
+			case c.Failure != nil:
+				messages[c.Name], types[c.Name] = c.Failure.Message, c.Failure.Type
+			case c.Skipped != nil:
+				messages[c.Name] = c.Skipped.Message
+			}
+		}
+	}
+	for name, want := range map[string]string{
+		"go 1.21.13":     "go 1.21.13 reached end of life on 2024-08-13, 49 days ago (CRITICAL)",
+		"nodejs 20.11.1": "nodejs 20.11.1 reaches end of life on 2026-04-30, in 90 days (HIGH); 7 release(s) behind 20.18.0",
+		"nodejs 18":      "nodejs 18 is end of life (HIGH)",
+		"rhel 8":         "rhel 8 reaches the end of extended support on 2032-05-31, in 2000 days (HIGH)",
+		"python 3.8":     "python 3.8 reached end of life on 2024-10-07, 3 days ago (CRITICAL); accepted by waiver: legacy batch job",
+	} {
+		if messages[name] != want {
+			t.Errorf("%s message = %q, want %q", name, messages[name], want)
+		}
+	}
+	if types["go 1.21.13"] != "CRITICAL" || types["nodejs 18"] != "HIGH" {
+		t.Errorf("failure types = %v, want the risk levels", types)
+	}
+	if _, ok := messages["flask 3.0"]; ok {
+		t.Error("a LOW finding failed or was skipped")
+	}
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Markdown is the GitHub-flavoured Markdown output format, for pull request
+// comments and documents.
+const Markdown = "markdown"
+
+// badgeColors are the shields.io colours of the severity bands.
+var badgeColors = map[string]string{
+	bandCritical: "critical",
+	bandHigh:     "red",
+	bandMedium:   "yellow",
+	bandLow:      "brightgreen",
+	bandAccepted: "blue",
+	bandNone:     "lightgrey",
+}
+
+// renderMarkdown writes a summary of the risk levels, the findings as a
+// table with risk badges, the accepted risks and any AI reports, or the
+// rows of a listing as a table.
+func renderMarkdown(w io.Writer, r *Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", r.Title())
+	if !r.GeneratedAt.IsZero() {
+		fmt.Fprintf(&b, "_Generated %s by eolctl %s_\n\n", r.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"), r.Version)
+	}
+
+	if r.Rows != nil {
+		r.Rows.writeMarkdown(&b, r.NoHeaders)
+	} else if len(r.Findings) == 0 {
+		b.WriteString("No findings.\n")
+	} else {
+		var summary []string
+		for _, c := range r.counts() {
+			summary = append(summary, fmt.Sprintf("%s %d", r.badge(c.Level), c.Count))
+		}
+		fmt.Fprintf(&b, "**%d component(s):** %s\n\n", len(r.Findings), strings.Join(summary, " · "))
+
+		columns := r.columns()
+		var headers, rules []string
+		for _, c := range columns {
+			headers = append(headers, c.header)
+			rules = append(rules, "---")
+		}
+		fmt.Fprintf(&b, "| %s |\n| %s |\n", strings.Join(headers, " | "), strings.Join(rules, " | "))
+		for _, f := range r.Findings {
+			cells := make([]string, len(columns))
+			for i, c := range columns {
+				value := c.value(f)
+				if c.level && value != "" {
+					cells[i] = r.badge(value)
+				} else {
+					cells[i] = markdownCell(value)
+				}
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+
+	var accepted []string
+	for _, f := range r.Findings {
+		if f.Waiver != nil {
+			accepted = append(accepted, fmt.Sprintf("- **%s** (%s): %s — owner %s, expires %s",
+				markdownCell(f.Name()), f.AcceptedRisk, markdownCell(f.Waiver.Reason), markdownCell(f.Waiver.Owner), f.Waiver.Expires))
+		}
+	}
+	if len(accepted) > 0 {
+		fmt.Fprintf(&b, "\n### Accepted risks\n\n%s\n", strings.Join(accepted, "\n"))
+	}
+	if r.Narrative != "" {
+		fmt.Fprintf(&b, "\n### AI risk summary\n\n%s\n", strings.TrimSpace(r.Narrative))
+	}
+	if r.Suggestions != "" {
+		fmt.Fprintf(&b, "\n### AI upgrade suggestions\n\n%s\n", strings.TrimSpace(r.Suggestions))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// badge returns a shields.io badge for a risk level, with the level as alt
+// text so it reads the same where images are not shown.
+func (r *Report) badge(level string) string {
+	text := strings.NewReplacer("-", "--", "_", "__", " ", "_").Replace(level)
+	return fmt.Sprintf("![%s](https://img.shields.io/badge/%s-%s)", level, url.PathEscape(text), badgeColors[r.band(level)])
+}
+
+// markdownCell escapes a value for a table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(s)
+}
+
+// writeMarkdown writes the rows as a table. Markdown tables need a header
+// row, so it is left blank when there is none or headers are turned off.
+func (rows *Rows) writeMarkdown(b *strings.Builder, noHeaders bool) {
+	if len(rows.Data) == 0 {
+		b.WriteString("No entries.\n")
+		return
+	}
+	width := len(rows.Header)
+	for _, row := range rows.Data {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	headers, rules := make([]string, width), make([]string, width)
+	for i := range rules {
+		if i < len(rows.Header) && !noHeaders {
+			headers[i] = markdownCell(rows.Header[i])
+		}
+		rules[i] = "---"
+	}
+	fmt.Fprintf(b, "| %s |\n| %s |\n", strings.Join(headers, " | "), strings.Join(rules, " | "))
+	for _, row := range rows.Data {
+		cells := make([]string, width)
+		for i := range row {
+			cells[i] = markdownCell(row[i])
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	}
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+func TestMarkdownCell(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"nodejs", "nodejs"},
+		{"a|b", `a\|b`},
+		{"|", `\|`},
+		{"line one\nline two", "line one<br>line two"},
+	}
+	for _, tt := range tests {
+		if got := markdownCell(tt.in); got != tt.want {
+			t.Errorf("markdownCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdownEscapesCells(t *testing.T) {
+	out := renderString(t, Markdown, &Report{
+		Source: SourceProject,
+		Levels: []string{"HIGH", "LOW"},
+		Findings: []Finding{
+			{Source: SourceProject, Location: "a|b/go.mod", Product: "go|lang", Version: "1.22.5", Latest: "1.22.8", EOL: "2025-02-11", Risk: "HIGH"},
+			{Source: SourceProject, Product: "python", Version: "3.8", EOL: "2024-10-07", Risk: waiver.Accepted, AcceptedRisk: "HIGH",
+				Waiver: &waiver.Waiver{Reason: "batch | cron\njobs", Owner: "data|ops", Expires: "2025-01-31"}},
+		},
+	})
+
+	// Every table row has as many unescaped pipes as the header row.
+	var header, rows []string
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "| ") {
+			continue
+		}
+		if header == nil {
+			header = []string{line}
+			continue
+		}
+		rows = append(rows, line)
+	}
+	if header == nil || len(rows) != 3 {
+		t.Fatalf("table = %q, %q; want a header, a rule and 2 rows:\n%s", header, rows, out)
+	}
+	cells := func(line string) int {
+		return strings.Count(line, "|") - strings.Count(line, `\|`)
+	}
+	for _, row := range rows {
+		if cells(row) != cells(header[0]) {
+			t.Errorf("row %q has %d cell separators, want %d", row, cells(row), cells(header[0]))
+		}
+	}
+	if !strings.Contains(rows[1], `| go\|lang |`) {
+		t.Errorf("product cell not escaped: %q", rows[1])
+	}
+	if want := `- **python 3.8** (HIGH): batch \| cron<br>jobs — owner data\|ops, expires 2025-01-31`; !strings.Contains(out, want) {
+		t.Errorf("accepted risk not escaped, want %q in:\n%s", want, out)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	r := clusterReport()
+	r.Narrative, r.Suggestions = "Upgrade nginx first.", "Move to nginx 1.27."
+	out := renderString(t, Markdown, r)
+
+	for _, want := range []string{
+		"## Cluster scan\n",
+		"_Generated 2024-10-01 12:00 UTC by eolctl 1.2.3_",
+		"**3 component(s):** ![CRITICAL](https://img.shields.io/badge/CRITICAL-critical) 1 · ![MEDIUM](https://img.shields.io/badge/MEDIUM-yellow) 1 · ![ACCEPTED](https://img.shields.io/badge/ACCEPTED-blue) 1",
+		"| Release | Namespace | Product | Version |",
+		"| ingress-nginx | ingress | nginx | 1.25.3 |",
+		"### Accepted risks",
+		"### AI risk summary\n\nUpgrade nginx first.\n",
+		"### AI upgrade suggestions\n\nMove to nginx 1.27.\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, out)
+		}
+	}
+
+	empty := renderString(t, Markdown, &Report{Source: SourceProject})
+	if !strings.Contains(empty, "No findings.") || strings.Contains(empty, "| ") {
+		t.Errorf("empty report = %q, want no table", empty)
+	}
+}
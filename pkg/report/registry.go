@@ -0,0 +1,69 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Renderer writes a report in one output format.
+type Renderer func(w io.Writer, r *Report) error
+
+var renderers = map[string]Renderer{}
+
+// Register makes a renderer available as an --output format. It panics if
+// the format is registered twice.
+func Register(format string, fn Renderer) {
+	if _, dup := renderers[format]; dup {
+		panic("report: renderer registered twice for " + format)
+	}
+	renderers[format] = fn
+}
+
+// Formats returns the registered formats in alphabetical order.
+func Formats() []string {
+	out := make([]string, 0, len(renderers))
+	for f := range renderers {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Validate reports an error for formats without a renderer.
+func Validate(format string) error {
+	if _, ok := renderers[format]; !ok {
+		return fmt.Errorf("invalid output type %q, use one of: %s", format, strings.Join(Formats(), ", "))
+	}
+	return nil
+}
+
+// EmbedsAIReports reports whether format includes the AI narrative and
+// upgrade suggestions of a report; other formats leave them to be printed
+// after the report.
+func EmbedsAIReports(format string) bool {
+	return format == Markdown || format == HTML
+}
+
+// Render writes r to w in format.
+func Render(w io.Writer, format string, r *Report) error {
+	fn, ok := renderers[format]
+	if !ok {
+		return Validate(format)
+	}
+	return fn(w, r)
+}
+
+func init() {
+	Register(JSON, renderJSON)
+	Register(YAML, renderYAML)
+	Register(Table, renderTable)
+	Register(CSV, delimited(','))
+	Register(TSV, delimited('\t'))
+	Register(ICS, renderICS)
+	Register(SARIF, renderSARIF)
+	Register(JUnit, renderJUnit)
+	Register(Markdown, renderMarkdown)
+	Register(HTML, renderHTML)
+}
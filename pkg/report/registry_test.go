@@ -0,0 +1,85 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderListing(t *testing.T) {
+	listing := func(noHeaders bool) *Report {
+		return &Report{
+			Heading:   "Available products",
+			NoHeaders: noHeaders,
+			Data:      []string{"go", "nodejs"},
+			Rows:      &Rows{Header: []string{"Product"}, Data: [][]string{{"go"}, {"nodejs"}}},
+		}
+	}
+
+	tests := []struct {
+		format    string
+		noHeaders bool
+		want      []string
+		notWant   []string
+	}{
+		{format: JSON, want: []string{"[\n  \"go\",\n  \"nodejs\"\n]\n"}},
+		{format: YAML, want: []string{"- go\n- nodejs\n"}},
+		{format: Table, want: []string{"PRODUCT", "| go ", "| nodejs "}},
+		{format: Table, noHeaders: true, want: []string{"| nodejs "}, notWant: []string{"PRODUCT"}},
+		{format: CSV, want: []string{"Product\ngo\nnodejs\n"}},
+		{format: CSV, noHeaders: true, want: []string{"go\nnodejs\n"}, notWant: []string{"Product"}},
+		{format: TSV, want: []string{"Product\ngo\nnodejs\n"}},
+		{format: Markdown, want: []string{"## Available products", "| Product |\n| --- |\n| go |\n| nodejs |\n"}},
+		{format: Markdown, noHeaders: true, want: []string{"|  |\n| --- |\n| go |"}, notWant: []string{"| Product |"}},
+		{format: HTML, want: []string{"<title>eolctl Available products</title>", "<th>Product</th>", "<td>nodejs</td>"}, notWant: []string{"component(s)"}},
+		{format: HTML, noHeaders: true, want: []string{"<td>go</td>"}, notWant: []string{"<thead>"}},
+		{format: SARIF, want: []string{`"version": "2.1.0"`, `"results": []`}},
+		{format: JUnit, want: []string{`tests="0"`}},
+		{format: ICS, want: []string{"BEGIN:VCALENDAR", "END:VCALENDAR"}, notWant: []string{"BEGIN:VEVENT"}},
+	}
+	for _, tt := range tests {
+		name := tt.format
+		if tt.noHeaders {
+			name += "/no headers"
+		}
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.format, listing(tt.noHeaders)); err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			out := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("output lacks %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("output contains %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderListingWithoutEntries(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			r := &Report{Heading: "Cache entries", Rows: &Rows{Header: []string{"Key"}}}
+			if err := Render(&bytes.Buffer{}, format, r); err != nil {
+				t.Errorf("Render: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, format := range Formats() {
+		if err := Validate(format); err != nil {
+			t.Errorf("Validate(%s): %v", format, err)
+		}
+	}
+	if err := Validate("pdf"); err == nil || !strings.Contains(err.Error(), "use one of: csv, html, ics") {
+		t.Errorf("Validate(pdf) = %v, want the list of formats", err)
+	}
+}
@@ -0,0 +1,228 @@
+// Package report defines the result model shared by every command that
+// rates components — get product, scan project and scan cluster — and a
+// registry of output renderers, so each format is written once and works
+// for every command.
+package report
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+// Sources of findings.
+const (
+	SourceProduct = "product"
+	SourceProject = "project"
+	SourceCluster = "cluster"
+)
+
+// Finding is the lifecycle status of one component: a product cycle, a
+// language in a project manifest or a Helm release.
+type Finding struct {
+	// Source is the kind of scan the finding came from.
+	Source string `json:"source"`
+	// Location identifies the component within the scan: the manifest path
+	// relative to the project directory, or namespace/release in a cluster.
+	Location string `json:"location,omitempty"`
+	// Line is the line of the manifest declaring the version, if known.
+	Line      int    `json:"line,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Release   string `json:"release,omitempty"`
+	Chart     string `json:"chart,omitempty"`
+
+	Product string `json:"product"`
+	Cycle   string `json:"cycle,omitempty"`
+	Version string `json:"version,omitempty"`
+	Latest  string `json:"latest,omitempty"`
+	// ReleasesBehind counts the releases of the cycle newer than Version:
+	// patch releases, or minor releases for major-only cycles such as
+	// nodejs 20. It is nil when only the cycle is known.
+	ReleasesBehind    *int   `json:"releases_behind,omitempty"`
+	PatchRisk         string `json:"patch_risk,omitempty"`
+	ReleaseDate       string `json:"release_date,omitempty"`
+	LatestReleaseDate string `json:"latest_release_date,omitempty"`
+	LTS               string `json:"lts,omitempty"`
+
+	EOL             string `json:"eol"`
+	Support         string `json:"support,omitempty"`
+	ExtendedSupport string `json:"extended_support,omitempty"`
+	SupportPhase    string `json:"support_phase,omitempty"`
+	Risk            string `json:"risk"`
+	// DaysUntilEOL is nil when there is no EOL date; negative once past it.
+	DaysUntilEOL *int `json:"days_until_eol,omitempty"`
+	// EOLDate is the date DaysUntilEOL counts down to: EOL, or the end of
+	// extended support when risk.count_extended_support is set.
+	EOLDate string `json:"eol_date,omitempty"`
+
+	// AcceptedRisk is the risk a waiver accepted; Risk is then ACCEPTED.
+	AcceptedRisk string         `json:"accepted_risk,omitempty"`
+	Waiver       *waiver.Waiver `json:"waiver,omitempty"`
+	// Baseline is "new" or "worsened" when comparing against a baseline.
+	Baseline string `json:"baseline,omitempty"`
+}
+
+// Name returns a short human-readable name for the finding, such as
+// "nodejs 20.11.1" or "ingress/nginx nginx 1.25.3".
+func (f Finding) Name() string {
+	name := f.Product
+	if v := f.DisplayVersion(); v != "" {
+		name += " " + v
+	}
+	if f.Source == SourceCluster && f.Location != "" {
+		name = f.Location + " " + name
+	}
+	return name
+}
+
+// DisplayVersion returns the installed version, or the cycle when no
+// specific version was given.
+func (f Finding) DisplayVersion() string {
+	if f.Version != "" {
+		return f.Version
+	}
+	return f.Cycle
+}
+
+// summary describes the lifecycle status of f at risk level, such as
+// "go 1.21 reached end of life on 2024-08-13, 430 days ago (CRITICAL)".
+func (f Finding) summary(level string) string {
+	event, date := "end of life", f.EOL
+	if f.EOLDate != "" {
+		date = f.EOLDate
+		if date != f.EOL && date == f.ExtendedSupport {
+			event = "the end of extended support"
+		}
+	}
+	_, dateErr := time.Parse(time.DateOnly, date)
+
+	var s string
+	switch {
+	case f.DaysUntilEOL != nil && dateErr == nil && *f.DaysUntilEOL < 0:
+		s = fmt.Sprintf("%s reached %s on %s, %d days ago", f.Name(), event, date, -*f.DaysUntilEOL)
+	case f.DaysUntilEOL != nil && dateErr == nil:
+		s = fmt.Sprintf("%s reaches %s on %s, in %d days", f.Name(), event, date, *f.DaysUntilEOL)
+	case f.EOL == "true":
+		s = fmt.Sprintf("%s is end of life", f.Name())
+	default:
+		s = fmt.Sprintf("%s has no announced end-of-life date", f.Name())
+	}
+	s += fmt.Sprintf(" (%s)", level)
+	if f.ReleasesBehind != nil && *f.ReleasesBehind > 0 {
+		s += fmt.Sprintf("; %d release(s) behind %s", *f.ReleasesBehind, f.Latest)
+	}
+	if f.Waiver != nil {
+		s += "; accepted by waiver: " + f.Waiver.Reason
+	}
+	return s
+}
+
+// fingerprint hashes what identifies the component across runs — source,
+// location, product and cycle — and extra, leaving out the installed
+// version and the risk so that upgrades within a cycle and risk changes keep
+// the same fingerprint.
+func (f Finding) fingerprint(extra ...string) string {
+	parts := append([]string{f.Source, f.Location, f.Product, f.Cycle}, extra...)
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:10])
+}
+
+// Report is the result of one command run.
+type Report struct {
+	// Source is the kind of scan, one of the Source constants.
+	Source string
+	// Version is the eolctl version that produced the report.
+	Version string
+	// Levels lists the risk levels of the policy in use, most severe first.
+	Levels      []string
+	GeneratedAt time.Time
+	Findings    []Finding
+	// FailLevel is the risk level at or above which junit testcases fail;
+	// empty means HIGH, or the level of Levels nearest to it.
+	FailLevel string
+	// NoHeaders drops the header row of the table, csv and tsv formats.
+	NoHeaders bool
+	// Narrative and Suggestions are the AI risk narrative and upgrade
+	// suggestions, embedded by the markdown and html formats.
+	Narrative   string
+	Suggestions string
+	// Reminders are the days before each calendar event at which the ics
+	// output raises an alarm; nil means DefaultReminders.
+	Reminders []int
+
+	// Heading replaces the title derived from Source.
+	Heading string
+	// Data and Rows are the result of a command that lists something other
+	// than findings, such as the available products or the cache entries.
+	// The json and yaml formats write Data and the table and document
+	// formats write Rows; ics, sarif and junit write a document without
+	// entries, as such a report has no findings.
+	Data interface{}
+	Rows *Rows
+}
+
+// Rows is the tabular layout of a listing.
+type Rows struct {
+	// Header names the columns; a nil Header is never printed.
+	Header []string
+	Data   [][]string
+}
+
+// Title describes the report, such as "Cluster scan".
+func (r *Report) Title() string {
+	if r.Heading != "" {
+		return r.Heading
+	}
+	switch r.Source {
+	case SourceProduct:
+		return "Product lifecycle"
+	case SourceProject:
+		return "Project scan"
+	case SourceCluster:
+		return "Cluster scan"
+	default:
+		return "EOL report"
+	}
+}
+
+// levelCount is the number of findings at a risk level.
+type levelCount struct {
+	Level string
+	Count int
+}
+
+// counts returns how many findings are at each level: the policy's levels
+// that occur, most severe first, then other values such as UNKNOWN or
+// ACCEPTED in order of appearance.
+func (r *Report) counts() []levelCount {
+	n := map[string]int{}
+	var others []string
+	for _, f := range r.Findings {
+		if n[f.Risk] == 0 && r.Rank(f.Risk) < 0 {
+			others = append(others, f.Risk)
+		}
+		n[f.Risk]++
+	}
+	var out []levelCount
+	for _, level := range append(append([]string{}, r.Levels...), others...) {
+		if n[level] > 0 {
+			out = append(out, levelCount{Level: level, Count: n[level]})
+		}
+	}
+	return out
+}
+
+// Rank returns the severity rank of level in r.Levels, 0 being the most
+// severe, or -1 for levels outside the policy such as UNKNOWN or ACCEPTED.
+func (r *Report) Rank(level string) int {
+	for i, l := range r.Levels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
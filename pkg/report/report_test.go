@@ -0,0 +1,58 @@
+package report
+
+import "testing"
+
+func TestFindingSummary(t *testing.T) {
+	days := func(n int) *int { return &n }
+	tests := []struct {
+		name    string
+		finding Finding
+		want    string
+	}{
+		{
+			name:    "past EOL",
+			finding: Finding{Product: "go", Cycle: "1.21", EOL: "2024-08-13", EOLDate: "2024-08-13", DaysUntilEOL: days(-430)},
+			want:    "go 1.21 reached end of life on 2024-08-13, 430 days ago (CRITICAL)",
+		},
+		{
+			name:    "future EOL",
+			finding: Finding{Product: "nodejs", Version: "20.11.1", EOL: "2026-04-30", EOLDate: "2026-04-30", DaysUntilEOL: days(90)},
+			want:    "nodejs 20.11.1 reaches end of life on 2026-04-30, in 90 days (CRITICAL)",
+		},
+		{
+			name: "extended support not counted",
+			finding: Finding{Product: "rhel", Cycle: "7", EOL: "2024-06-30", ExtendedSupport: "2028-06-30",
+				SupportPhase: "extended-support", EOLDate: "2024-06-30", DaysUntilEOL: days(-100)},
+			want: "rhel 7 reached end of life on 2024-06-30, 100 days ago (CRITICAL)",
+		},
+		{
+			name: "extended support counted",
+			finding: Finding{Product: "rhel", Cycle: "7", EOL: "2024-06-30", ExtendedSupport: "2028-06-30",
+				SupportPhase: "extended-support", EOLDate: "2028-06-30", DaysUntilEOL: days(1200)},
+			want: "rhel 7 reaches the end of extended support on 2028-06-30, in 1200 days (CRITICAL)",
+		},
+		{
+			name:    "EOL without date",
+			finding: Finding{Product: "alpine", Cycle: "3.14", EOL: "true", DaysUntilEOL: days(0)},
+			want:    "alpine 3.14 is end of life (CRITICAL)",
+		},
+		{
+			name:    "no EOL date",
+			finding: Finding{Product: "debian", Cycle: "13", EOL: "false"},
+			want:    "debian 13 has no announced end-of-life date (CRITICAL)",
+		},
+		{
+			name: "releases behind",
+			finding: Finding{Product: "python", Version: "3.12.1", Latest: "3.12.7", EOL: "2028-10-31", EOLDate: "2028-10-31",
+				DaysUntilEOL: days(700), ReleasesBehind: days(6)},
+			want: "python 3.12.1 reaches end of life on 2028-10-31, in 700 days (CRITICAL); 6 release(s) behind 3.12.7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.finding.summary("CRITICAL"); got != tt.want {
+				t.Errorf("summary() = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
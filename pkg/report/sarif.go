@@ -0,0 +1,258 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+// SARIF is the SARIF 2.1.0 output format, for code-scanning dashboards.
+const SARIF = "sarif"
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolURI      = "https://github.com/asafdavid23/eolctl"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	HelpURI              string             `json:"helpUri"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	Properties          map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// renderSARIF writes one result per finding, under a rule per risk level.
+// Manifest findings point at the file and line declaring the version;
+// cluster findings carry their release as a logical location. Waived
+// findings are reported as suppressed under the rule of the risk the
+// waiver accepted.
+func renderSARIF(w io.Writer, r *Report) error {
+	var rules []sarifRule
+	index := map[string]int{}
+	ruleFor := func(level string) int {
+		if i, ok := index[level]; ok {
+			return i
+		}
+		index[level] = len(rules)
+		rules = append(rules, sarifRule{
+			ID:                   ruleID(level),
+			Name:                 "EOLRisk" + ruleName(level),
+			ShortDescription:     sarifMessage{Text: fmt.Sprintf("End-of-life risk %s", level)},
+			FullDescription:      sarifMessage{Text: fmt.Sprintf("The component's release cycle is rated %s by the eolctl risk policy, based on its end-of-life and support dates.", level)},
+			HelpURI:              toolURI + "#risk-levels",
+			DefaultConfiguration: sarifConfiguration{Level: r.sarifLevel(level)},
+		})
+		return index[level]
+	}
+	// Declare the policy's levels in order so rule indexes are stable.
+	for _, level := range r.Levels {
+		ruleFor(level)
+	}
+
+	results := []sarifResult{}
+	for _, f := range r.Findings {
+		level := f.Risk
+		if f.AcceptedRisk != "" {
+			level = f.AcceptedRisk
+		}
+		result := sarifResult{
+			RuleID:              ruleID(level),
+			RuleIndex:           ruleFor(level),
+			Level:               r.sarifLevel(level),
+			Message:             sarifMessage{Text: f.summary(level)},
+			PartialFingerprints: map[string]string{"eolctlFinding/v1": f.fingerprint()},
+			Properties:          f.sarifProperties(),
+		}
+		if loc := f.sarifLocation(); loc != nil {
+			result.Locations = []sarifLocation{*loc}
+		}
+		if f.Waiver != nil {
+			result.Suppressions = []sarifSuppression{{
+				Kind:          "external",
+				Justification: fmt.Sprintf("%s (owner %s, expires %s)", f.Waiver.Reason, f.Waiver.Owner, f.Waiver.Expires),
+			}}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "eolctl",
+				Version:        r.Version,
+				InformationURI: toolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifLevel maps a risk level to a SARIF level: the most severe half of
+// the policy's levels are errors, the least severe level a note and the
+// rest, including levels outside the policy, warnings.
+func (r *Report) sarifLevel(level string) string {
+	rank, n := r.Rank(level), len(r.Levels)
+	switch {
+	case level == waiver.Accepted, rank < 0:
+		return "warning"
+	case rank == n-1:
+		return "note"
+	case rank < n/2:
+		return "error"
+	default:
+		return "warning"
+	}
+}
+
+// sarifLocation returns where f was found: the manifest for project
+// findings, the release for cluster findings and nothing for products.
+func (f Finding) sarifLocation() *sarifLocation {
+	switch {
+	case f.Location == "":
+		return nil
+	case f.Source == SourceCluster:
+		return &sarifLocation{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: f.Location, Kind: "module"}}}
+	default:
+		loc := &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: f.Location, URIBaseID: "%SRCROOT%"}}
+		if f.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: f.Line}
+		}
+		return &sarifLocation{PhysicalLocation: loc}
+	}
+}
+
+func (f Finding) sarifProperties() map[string]any {
+	props := map[string]any{"product": f.Product, "risk": f.Risk, "eol": f.EOL}
+	for key, value := range map[string]string{
+		"cycle":         f.Cycle,
+		"version":       f.Version,
+		"latest":        f.Latest,
+		"support_phase": f.SupportPhase,
+		"patch_risk":    f.PatchRisk,
+		"baseline":      f.Baseline,
+	} {
+		if value != "" {
+			props[key] = value
+		}
+	}
+	if f.DaysUntilEOL != nil {
+		props["days_until_eol"] = *f.DaysUntilEOL
+	}
+	if f.ReleasesBehind != nil {
+		props["releases_behind"] = *f.ReleasesBehind
+	}
+	return props
+}
+
+// ruleID returns the rule ID of a risk level, such as "eol/critical".
+func ruleID(level string) string {
+	return "eol/" + slug(level)
+}
+
+// ruleName returns level in PascalCase, such as "Critical".
+func ruleName(level string) string {
+	var sb strings.Builder
+	for _, word := range strings.Split(slug(level), "-") {
+		if word != "" {
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return sb.String()
+}
+
+// slug lowercases level and replaces everything but letters and digits
+// with dashes.
+func slug(level string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, level)
+}
@@ -0,0 +1,162 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asafdavid23/eolctl/pkg/ai"
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+const testGoMod = `module example.com/api
+
+go 1.21.13
+
+require github.com/spf13/cobra v1.8.1
+`
+
+func projectReport() *Report {
+	return &Report{
+		Source:  SourceProject,
+		Version: "1.2.3",
+		Levels:  []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"},
+		Findings: []Finding{
+			{
+				Source: SourceProject, Location: "services/api/go.mod", Line: ai.LocateVersion("services/api/go.mod", []byte(testGoMod), "1.21.13"),
+				Product: "go", Cycle: "1.21", Version: "1.21.13", Latest: "1.21.13", EOL: "2024-08-13", SupportPhase: "eol",
+				Risk: "CRITICAL", DaysUntilEOL: days(-49), EOLDate: "2024-08-13",
+			},
+			{
+				Source: SourceProject, Location: "web/package.json", Line: 9,
+				Product: "nodejs", Cycle: "18", Version: "18.20.4", Latest: "18.20.5", ReleasesBehind: days(1), EOL: "2025-04-30", SupportPhase: "security-only",
+				Risk: waiver.Accepted, AcceptedRisk: "HIGH", DaysUntilEOL: days(211), EOLDate: "2025-04-30",
+				Waiver: &waiver.Waiver{Reason: "migrating to 22 in Q1", Owner: "web-team", Expires: "2025-03-31"},
+			},
+			{
+				Source: SourceProject, Location: "requirements.txt",
+				Product: "python", Cycle: "3.12", Version: "3.12", EOL: "2028-10-31", SupportPhase: "active",
+				Risk: "LOW", DaysUntilEOL: days(1491), EOLDate: "2028-10-31",
+			},
+		},
+	}
+}
+
+func days(d int) *int { return &d }
+
+func TestRenderSARIFGolden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, SARIF, projectReport()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "project.sarif")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file (run go test -update to create it): %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("SARIF output differs from %s (run go test -update if the change is intended):\n%s", golden, buf.String())
+	}
+}
+
+// TestRenderSARIFValid checks the parts of the SARIF 2.1.0 schema that
+// code-scanning services reject uploads for.
+func TestRenderSARIFValid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, SARIF, projectReport()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var log struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				Suppressions []struct {
+					Kind string `json:"kind"`
+				} `json:"suppressions"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || log.Schema != "https://json.schemastore.org/sarif-2.1.0.json" {
+		t.Errorf("version %q, $schema %q; want 2.1.0 and the 2.1.0 schema", log.Version, log.Schema)
+	}
+	if len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != "eolctl" {
+		t.Fatalf("runs = %+v, want one eolctl run", log.Runs)
+	}
+	run := log.Runs[0]
+	levels := map[string]bool{"none": true, "note": true, "warning": true, "error": true}
+	for _, rule := range run.Tool.Driver.Rules {
+		if !levels[rule.DefaultConfiguration.Level] {
+			t.Errorf("rule %s has level %q", rule.ID, rule.DefaultConfiguration.Level)
+		}
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("%d results, want 3", len(run.Results))
+	}
+	for i, res := range run.Results {
+		if res.RuleIndex < 0 || res.RuleIndex >= len(run.Tool.Driver.Rules) || run.Tool.Driver.Rules[res.RuleIndex].ID != res.RuleID {
+			t.Errorf("result %d: ruleIndex %d does not point at rule %s", i, res.RuleIndex, res.RuleID)
+		}
+		if !levels[res.Level] || res.Message.Text == "" {
+			t.Errorf("result %d: level %q, message %q", i, res.Level, res.Message.Text)
+		}
+		for _, s := range res.Suppressions {
+			if s.Kind != "inSource" && s.Kind != "external" {
+				t.Errorf("result %d: suppression kind %q", i, s.Kind)
+			}
+		}
+	}
+
+	goResult := run.Results[0].Locations[0].PhysicalLocation
+	if goResult.ArtifactLocation.URI != "services/api/go.mod" || goResult.Region == nil || goResult.Region.StartLine != 3 {
+		t.Errorf("go result location = %+v, want line 3 of services/api/go.mod, the go directive", goResult)
+	}
+	if loc := run.Results[2].Locations[0].PhysicalLocation; loc.Region != nil {
+		t.Errorf("result without a line has region %+v", loc.Region)
+	}
+	if len(run.Results[1].Suppressions) != 1 || run.Results[1].RuleID != "eol/high" {
+		t.Errorf("waived result = rule %s, suppressions %+v; want eol/high, suppressed", run.Results[1].RuleID, run.Results[1].Suppressions)
+	}
+}
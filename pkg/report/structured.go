@@ -0,0 +1,50 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/asafdavid23/eolctl/internal/yamljson"
+)
+
+// Structured output formats. YAML uses the encoding/json field names, so it
+// has the same shape as JSON output.
+const (
+	JSON = "json"
+	YAML = "yaml"
+)
+
+// data returns what the structured formats write: the data of a listing,
+// or the findings, never nil, so an empty report prints an empty list
+// rather than null.
+func (r *Report) data() interface{} {
+	if r.Rows != nil {
+		return r.Data
+	}
+	if r.Findings == nil {
+		return []Finding{}
+	}
+	return r.Findings
+}
+
+func renderJSON(w io.Writer, r *Report) error {
+	data, err := json.MarshalIndent(r.data(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results to JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func renderYAML(w io.Writer, r *Report) error {
+	data, err := json.Marshal(r.data())
+	if err != nil {
+		return fmt.Errorf("failed to marshal results to YAML: %w", err)
+	}
+	if data, err = yamljson.FromJSON(data); err != nil {
+		return fmt.Errorf("failed to marshal results to YAML: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,261 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+// Tabular output formats.
+const (
+	Table = "table"
+	CSV   = "csv"
+	TSV   = "tsv"
+)
+
+// column is a table column. Optional columns are dropped when no finding
+// has a value for them; level columns hold risk levels.
+type column struct {
+	header   string
+	value    func(Finding) string
+	optional bool
+	level    bool
+}
+
+var (
+	colRelease           = column{header: "Release", value: func(f Finding) string { return f.Release }}
+	colNamespace         = column{header: "Namespace", value: func(f Finding) string { return f.Namespace }}
+	colProduct           = column{header: "Product", value: func(f Finding) string { return f.Product }}
+	colCycle             = column{header: "Cycle", value: func(f Finding) string { return f.Cycle }}
+	colVersion           = column{header: "Version", value: func(f Finding) string { return f.Version }}
+	colLatest            = column{header: "Latest", value: func(f Finding) string { return f.Latest }}
+	colBehind            = column{header: "Behind", value: func(f Finding) string { return formatCount(f.ReleasesBehind) }}
+	colPatchRisk         = column{header: "Patch Risk", value: func(f Finding) string { return f.PatchRisk }, level: true}
+	colLatestReleaseDate = column{header: "LatestReleaseDate", value: func(f Finding) string { return f.LatestReleaseDate }}
+	colReleaseDate       = column{header: "ReleaseDate", value: func(f Finding) string { return f.ReleaseDate }}
+	colLTS               = column{header: "LTS", value: func(f Finding) string { return f.LTS }}
+	colEOL               = column{header: "EOL", value: func(f Finding) string { return f.EOL }}
+	colSupport           = column{header: "Support", value: func(f Finding) string { return f.Support }}
+	colPhase             = column{header: "Phase", value: func(f Finding) string { return f.SupportPhase }}
+	colRisk              = column{header: "Risk", value: func(f Finding) string { return f.Risk }, level: true}
+
+	colLocation        = column{header: "Location", value: func(f Finding) string { return f.Location }}
+	colLine            = column{header: "Line", value: func(f Finding) string { return formatInt(f.Line) }}
+	colChart           = column{header: "Chart", value: func(f Finding) string { return f.Chart }}
+	colExtendedSupport = column{header: "Extended Support", value: func(f Finding) string { return f.ExtendedSupport }}
+	colDaysUntilEOL    = column{header: "Days Until EOL", value: func(f Finding) string { return formatCount(f.DaysUntilEOL) }}
+	colAcceptedRisk    = column{header: "Accepted Risk", value: func(f Finding) string { return f.AcceptedRisk }, level: true}
+	colWaiverOwner     = column{header: "Waiver Owner", value: func(f Finding) string { return waiverField(f, func(w *waiver.Waiver) string { return w.Owner }) }}
+	colWaiverExpires   = column{header: "Waiver Expires", value: func(f Finding) string { return waiverField(f, func(w *waiver.Waiver) string { return w.Expires }) }}
+	colBaseline        = column{header: "Baseline", value: func(f Finding) string { return f.Baseline }}
+)
+
+func optional(c column) column {
+	c.optional = true
+	return c
+}
+
+// tableColumns are the columns shown for each source.
+var tableColumns = map[string][]column{
+	SourceProduct: {colCycle, optional(colVersion), colLatest, optional(colBehind), optional(colPatchRisk), colLatestReleaseDate, colReleaseDate, colLTS, colEOL, colSupport, colPhase, colRisk},
+	SourceProject: {colProduct, colVersion, colLatest, colBehind, colPatchRisk, colEOL, colPhase, colRisk},
+	SourceCluster: {colRelease, colNamespace, colProduct, colVersion, colLatest, colBehind, colPatchRisk, colEOL, colPhase, colRisk},
+}
+
+// exportColumns are the columns of the csv and tsv formats for each source:
+// every column of the table and the fields it leaves out, always in this
+// order so spreadsheets and scripts can rely on it.
+var exportColumns = map[string][]column{
+	SourceProduct: {colCycle, colVersion, colLatest, colBehind, colPatchRisk, colLatestReleaseDate, colReleaseDate, colLTS, colEOL, colSupport, colExtendedSupport, colPhase, colRisk, colDaysUntilEOL, colAcceptedRisk, colWaiverOwner, colWaiverExpires},
+	SourceProject: {colLocation, colLine, colProduct, colCycle, colVersion, colLatest, colBehind, colPatchRisk, colEOL, colSupport, colExtendedSupport, colPhase, colRisk, colDaysUntilEOL, colAcceptedRisk, colWaiverOwner, colWaiverExpires, colBaseline},
+	SourceCluster: {colNamespace, colRelease, colChart, colProduct, colCycle, colVersion, colLatest, colBehind, colPatchRisk, colEOL, colSupport, colExtendedSupport, colPhase, colRisk, colDaysUntilEOL, colAcceptedRisk, colWaiverOwner, colWaiverExpires, colBaseline},
+}
+
+// renderTable draws the findings as a table with risk levels coloured by
+// severity, or the rows of a listing.
+func renderTable(w io.Writer, r *Report) error {
+	table := tablewriter.NewWriter(w)
+	table.SetAutoWrapText(false)
+
+	if r.Rows != nil {
+		if r.Rows.Header != nil && !r.NoHeaders {
+			table.SetHeader(r.Rows.Header)
+		}
+		table.AppendBulk(r.Rows.Data)
+		table.Render()
+		return nil
+	}
+
+	shown := r.columns()
+	headers := make([]string, len(shown))
+	for i, c := range shown {
+		headers[i] = c.header
+	}
+	if !r.NoHeaders {
+		table.SetHeader(headers)
+	}
+
+	for _, f := range r.Findings {
+		row := make([]string, len(shown))
+		colors := make([]tablewriter.Colors, len(shown))
+		for i, c := range shown {
+			row[i] = c.value(f)
+			colors[i] = r.levelColor(row[i])
+		}
+		table.Rich(row, colors)
+	}
+	table.Render()
+	return nil
+}
+
+// columns returns the columns shown for r's source, without optional
+// columns no finding has a value for.
+func (r *Report) columns() []column {
+	columns, ok := tableColumns[r.Source]
+	if !ok {
+		columns = tableColumns[SourceProject]
+	}
+
+	var shown []column
+	for _, c := range columns {
+		if c.optional && !anyValue(r.Findings, c) {
+			continue
+		}
+		shown = append(shown, c)
+	}
+	return shown
+}
+
+func anyValue(findings []Finding, c column) bool {
+	for _, f := range findings {
+		if c.value(f) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity bands of risk levels.
+const (
+	bandCritical = "critical"
+	bandHigh     = "high"
+	bandMedium   = "medium"
+	bandLow      = "low"
+	bandAccepted = "accepted"
+	bandNone     = "none"
+)
+
+// band places level by its rank in the policy: the most severe level is
+// critical, the least severe low, the rest of the upper half high and the
+// remainder medium. Values that are not levels fall in no band.
+func (r *Report) band(level string) string {
+	rank, n := r.Rank(level), len(r.Levels)
+	switch {
+	case level == waiver.Accepted:
+		return bandAccepted
+	case rank < 0:
+		return bandNone
+	case rank == 0:
+		return bandCritical
+	case rank == n-1:
+		return bandLow
+	case rank < n/2:
+		return bandHigh
+	default:
+		return bandMedium
+	}
+}
+
+// levelColor returns the terminal colour of level's severity band.
+func (r *Report) levelColor(level string) tablewriter.Colors {
+	switch r.band(level) {
+	case bandAccepted:
+		return tablewriter.Colors{tablewriter.FgCyanColor}
+	case bandCritical:
+		return tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+	case bandHigh:
+		return tablewriter.Colors{tablewriter.FgRedColor}
+	case bandMedium:
+		return tablewriter.Colors{tablewriter.FgYellowColor}
+	case bandLow:
+		return tablewriter.Colors{tablewriter.FgGreenColor}
+	default:
+		return tablewriter.Colors{}
+	}
+}
+
+// formatCount renders an optional count such as patches behind, empty
+// when unknown.
+func formatCount(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// formatInt renders n, empty when zero.
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// waiverField returns a field of f's waiver, empty when it has none.
+func waiverField(f Finding, field func(*waiver.Waiver) string) string {
+	if f.Waiver == nil {
+		return ""
+	}
+	return field(f.Waiver)
+}
+
+// delimited returns a renderer writing the export columns, or the rows of a
+// listing, as RFC 4180 records separated by comma, so fields containing the
+// separator, quotes or line breaks are quoted.
+func delimited(comma rune) Renderer {
+	return func(w io.Writer, r *Report) error {
+		header, data := r.exportRows()
+		cw := csv.NewWriter(w)
+		cw.Comma = comma
+		if header != nil && !r.NoHeaders {
+			if err := cw.Write(header); err != nil {
+				return fmt.Errorf("failed to write delimited output: %w", err)
+			}
+		}
+		if err := cw.WriteAll(data); err != nil {
+			return fmt.Errorf("failed to write delimited output: %w", err)
+		}
+		return nil
+	}
+}
+
+// exportRows lays out the findings in the export columns of r's source, or
+// returns the rows of a listing.
+func (r *Report) exportRows() ([]string, [][]string) {
+	if r.Rows != nil {
+		return r.Rows.Header, r.Rows.Data
+	}
+
+	columns, ok := exportColumns[r.Source]
+	if !ok {
+		columns = exportColumns[SourceProject]
+	}
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.header
+	}
+	var data [][]string
+	for _, f := range r.Findings {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = c.value(f)
+		}
+		data = append(data, row)
+	}
+	return header, data
+}
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/asafdavid23/eolctl/pkg/waiver"
+)
+
+func TestRenderCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		report    *Report
+		noHeaders bool
+		want      string
+	}{
+		{
+			name: "fields with commas, quotes and newlines are quoted",
+			report: &Report{Source: SourceProduct, Findings: []Finding{{
+				Source: SourceProduct, Product: "acme", Cycle: "2, LTS", Latest: `2.4 "final"`, EOL: "2025-01-31", Risk: waiver.Accepted, AcceptedRisk: "HIGH",
+				Waiver: &waiver.Waiver{Owner: "platform\nteam", Expires: "2025-01-01"},
+			}}},
+			want: "Cycle,Version,Latest,Behind,Patch Risk,LatestReleaseDate,ReleaseDate,LTS,EOL,Support,Extended Support,Phase,Risk,Days Until EOL,Accepted Risk,Waiver Owner,Waiver Expires\n" +
+				"\"2, LTS\",,\"2.4 \"\"final\"\"\",,,,,,2025-01-31,,,,ACCEPTED,,HIGH,\"platform\nteam\",2025-01-01\n",
+		},
+		{
+			name:      "no headers",
+			noHeaders: true,
+			report: &Report{Source: SourceProject, Findings: []Finding{
+				{Source: SourceProject, Location: "go.mod", Line: 3, Product: "go", Cycle: "1.22", Version: "1.22.5", ReleasesBehind: days(3), DaysUntilEOL: days(-10), Risk: "CRITICAL"},
+			}},
+			want: "go.mod,3,go,1.22,1.22.5,,3,,,,,,CRITICAL,-10,,,,\n",
+		},
+		{
+			name:   "cluster columns",
+			report: &Report{Source: SourceCluster, Findings: []Finding{{Source: SourceCluster, Namespace: "ingress", Release: "nginx", Chart: "ingress-nginx", Product: "nginx", Baseline: "new", Risk: "LOW"}}},
+			want: "Namespace,Release,Chart,Product,Cycle,Version,Latest,Behind,Patch Risk,EOL,Support,Extended Support,Phase,Risk,Days Until EOL,Accepted Risk,Waiver Owner,Waiver Expires,Baseline\n" +
+				"ingress,nginx,ingress-nginx,nginx,,,,,,,,,,LOW,,,,,new\n",
+		},
+		{
+			name:   "no findings still writes the header",
+			report: &Report{Source: SourceProject},
+			want:   "Location,Line,Product,Cycle,Version,Latest,Behind,Patch Risk,EOL,Support,Extended Support,Phase,Risk,Days Until EOL,Accepted Risk,Waiver Owner,Waiver Expires,Baseline\n",
+		},
+		{
+			name:   "listing rows",
+			report: &Report{Rows: &Rows{Header: []string{"Key", "Size"}, Data: [][]string{{"https://example.com/a,b.json", "1.0 KiB"}, {`say "hi"`, "12 B"}}}},
+			want:   "Key,Size\n\"https://example.com/a,b.json\",1.0 KiB\n\"say \"\"hi\"\"\",12 B\n",
+		},
+		{
+			name:      "listing rows without headers",
+			noHeaders: true,
+			report:    &Report{Rows: &Rows{Header: []string{"Product"}, Data: [][]string{{"go"}}}},
+			want:      "go\n",
+		},
+		{
+			name:   "listing rows without a header row",
+			report: &Report{Rows: &Rows{Data: [][]string{{"Entries", "11"}}}},
+			want:   "Entries,11\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.NoHeaders = tt.noHeaders
+			out := renderString(t, CSV, tt.report)
+			if out != tt.want {
+				t.Errorf("csv =\n%s\nwant\n%s", out, tt.want)
+			}
+			if _, err := csv.NewReader(strings.NewReader(out)).ReadAll(); err != nil {
+				t.Errorf("csv does not parse back: %v", err)
+			}
+		})
+	}
+}
+
+func TestRenderTSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		report    *Report
+		noHeaders bool
+		want      string
+	}{
+		{
+			name:      "fields with tabs, quotes and newlines are quoted, commas are not",
+			noHeaders: true,
+			report: &Report{Rows: &Rows{Header: []string{"Key", "Note"}, Data: [][]string{
+				{"a\tb", "1, 2"},
+				{`say "hi"`, "two\nlines"},
+			}}},
+			want: "\"a\tb\"\t1, 2\n\"say \"\"hi\"\"\"\t\"two\nlines\"\n",
+		},
+		{
+			name:   "headers",
+			report: &Report{Rows: &Rows{Header: []string{"Key", "Size"}, Data: [][]string{{"go.json", "1.0 KiB"}}}},
+			want:   "Key\tSize\ngo.json\t1.0 KiB\n",
+		},
+		{
+			name:   "findings",
+			report: &Report{Source: SourceProject, Findings: []Finding{{Source: SourceProject, Location: "web/package.json", Product: "nodejs", Version: "20.11.1", Risk: "HIGH"}}},
+			want: "Location\tLine\tProduct\tCycle\tVersion\tLatest\tBehind\tPatch Risk\tEOL\tSupport\tExtended Support\tPhase\tRisk\tDays Until EOL\tAccepted Risk\tWaiver Owner\tWaiver Expires\tBaseline\n" +
+				"web/package.json\t\tnodejs\t\t20.11.1\t\t\t\t\t\t\t\tHIGH\t\t\t\t\t\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.NoHeaders = tt.noHeaders
+			out := renderString(t, TSV, tt.report)
+			if out != tt.want {
+				t.Errorf("tsv =\n%q\nwant\n%q", out, tt.want)
+			}
+			r := csv.NewReader(strings.NewReader(out))
+			r.Comma = '\t'
+			if _, err := r.ReadAll(); err != nil {
+				t.Errorf("tsv does not parse back: %v", err)
+			}
+		})
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	findings := []Finding{
+		{Source: SourceProduct, Product: "go", Cycle: "1.23", Latest: "1.23.4", EOL: "false", Risk: "LOW"},
+		{Source: SourceProduct, Product: "go", Cycle: "1.21", Latest: "1.21.13", EOL: "2024-08-13", Risk: "CRITICAL"},
+	}
+	tests := []struct {
+		name      string
+		report    *Report
+		noHeaders bool
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:    "optional columns without values are dropped",
+			report:  &Report{Source: SourceProduct, Findings: findings},
+			want:    []string{"CYCLE", "LATEST", "RISK", "1.21.13", "2024-08-13"},
+			notWant: []string{"VERSION", "BEHIND", "PATCH RISK"},
+		},
+		{
+			name:      "no headers",
+			report:    &Report{Source: SourceProduct, Findings: findings},
+			noHeaders: true,
+			want:      []string{"1.23.4"},
+			notWant:   []string{"CYCLE"},
+		},
+		{
+			name:   "listing rows",
+			report: &Report{Rows: &Rows{Header: []string{"Key", "Size"}, Data: [][]string{{"go.json", "1.0 KiB"}}}},
+			want:   []string{"KEY", "SIZE", "go.json", "1.0 KiB"},
+		},
+		{
+			name:      "listing rows without headers",
+			report:    &Report{Rows: &Rows{Header: []string{"Key"}, Data: [][]string{{"go.json"}}}},
+			noHeaders: true,
+			want:      []string{"go.json"},
+			notWant:   []string{"KEY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.NoHeaders = tt.noHeaders
+			out := renderString(t, Table, tt.report)
+			for _, s := range tt.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("table lacks %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("table contains %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Package resolve maps user-supplied product names — typos, common aliases,
+// Helm chart names — to endoflife.date product slugs.
+package resolve
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultAliases maps common alternative names to endoflife.date slugs.
+var DefaultAliases = map[string]string{
+	"node":                  "nodejs",
+	"node.js":               "nodejs",
+	"postgres":              "postgresql",
+	"pg":                    "postgresql",
+	"postgresql-ha":         "postgresql",
+	"k8s":                   "kubernetes",
+	"kube":                  "kubernetes",
+	"eks":                   "amazon-eks",
+	"aks":                   "azure-kubernetes-service",
+	"gke":                   "google-kubernetes-engine",
+	"py":                    "python",
+	"python3":               "python",
+	"golang":                "go",
+	"redis-cluster":         "redis",
+	"mongo":                 "mongodb",
+	"mongodb-sharded":       "mongodb",
+	"maria":                 "mariadb",
+	"elastic":               "elasticsearch",
+	"es":                    "elasticsearch",
+	"rabbit":                "rabbitmq",
+	"redhat":                "rhel",
+	"red-hat":               "rhel",
+	"dotnetcore":            "dotnet",
+	".net":                  "dotnet",
+	"ror":                   "rails",
+	"ruby-on-rails":         "rails",
+	"kube-prometheus-stack": "prometheus",
+	"tf":                    "terraform",
+}
+
+// maxSuggestions bounds how many "did you mean" candidates are returned.
+const maxSuggestions = 5
+
+// NotFoundError is returned when a name matches no product. Suggestions
+// holds the closest known products, best first.
+type NotFoundError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("unknown product %q", e.Name)
+	}
+	return fmt.Sprintf("unknown product %q, did you mean: %s?", e.Name, strings.Join(e.Suggestions, ", "))
+}
+
+// Resolver resolves names against a list of known product slugs.
+type Resolver struct {
+	products map[string]bool
+	sorted   []string
+	aliases  map[string]string
+}
+
+// New returns a Resolver for products. aliases extend and override
+// DefaultAliases; aliases pointing at unknown products are ignored.
+func New(products []string, aliases map[string]string) *Resolver {
+	r := &Resolver{
+		products: make(map[string]bool, len(products)),
+		aliases:  make(map[string]string, len(DefaultAliases)+len(aliases)),
+	}
+	for _, p := range products {
+		r.products[p] = true
+	}
+	r.sorted = append([]string(nil), products...)
+	sort.Strings(r.sorted)
+
+	for _, m := range []map[string]string{DefaultAliases, aliases} {
+		for alias, product := range m {
+			if r.products[product] {
+				r.aliases[normalize(alias)] = product
+			}
+		}
+	}
+	return r
+}
+
+// Lookup returns the product name resolves to exactly, either directly or
+// through the alias table, after normalising case and separators.
+func (r *Resolver) Lookup(name string) (string, bool) {
+	if r.products[name] {
+		return name, true
+	}
+	n := normalize(name)
+	if r.products[n] {
+		return n, true
+	}
+	if p, ok := r.aliases[n]; ok {
+		return p, true
+	}
+	return "", false
+}
+
+// Resolve returns the product for name, or a *NotFoundError listing the
+// closest products when there is no exact or alias match.
+func (r *Resolver) Resolve(name string) (string, error) {
+	if p, ok := r.Lookup(name); ok {
+		return p, nil
+	}
+	return "", &NotFoundError{Name: name, Suggestions: r.Suggest(name)}
+}
+
+// Best returns the product for name when it is unambiguous: an exact or
+// alias match, or a single suggestion one edit away with no runner-up as
+// close. It is meant for unattended resolution such as cluster scans.
+func (r *Resolver) Best(name string) (string, bool) {
+	if p, ok := r.Lookup(name); ok {
+		return p, true
+	}
+	ranked := r.rank(normalize(name))
+	switch {
+	case len(ranked) == 0 || ranked[0].score > 1:
+		return "", false
+	case len(ranked) == 1 || ranked[0].score < ranked[1].score:
+		return ranked[0].product, true
+	default:
+		return "", false
+	}
+}
+
+// Suggest returns up to five products close to name, best first.
+func (r *Resolver) Suggest(name string) []string {
+	ranked := r.rank(normalize(name))
+	out := make([]string, 0, maxSuggestions)
+	for _, c := range ranked {
+		if len(out) == maxSuggestions {
+			break
+		}
+		out = append(out, c.product)
+	}
+	return out
+}
+
+type candidate struct {
+	product string
+	score   int
+}
+
+// rank scores every product and alias against name and returns the
+// products within the edit-distance budget, closest first. A product that
+// starts with name (e.g. "postgre") ranks as if it were one edit away.
+func (r *Resolver) rank(name string) []candidate {
+	if name == "" {
+		return nil
+	}
+	budget := 1 + len(name)/4
+
+	best := make(map[string]int)
+	consider := func(key, product string) {
+		score := distance(name, key)
+		if len(name) >= 3 && strings.HasPrefix(key, name) && score > 1 {
+			score = 1
+		}
+		if score > budget {
+			return
+		}
+		if s, ok := best[product]; !ok || score < s {
+			best[product] = score
+		}
+	}
+	for _, p := range r.sorted {
+		consider(p, p)
+	}
+	for alias, p := range r.aliases {
+		consider(alias, p)
+	}
+
+	ranked := make([]candidate, 0, len(best))
+	for p, s := range best {
+		ranked = append(ranked, candidate{product: p, score: s})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score < ranked[j].score
+		}
+		return ranked[i].product < ranked[j].product
+	})
+	return ranked
+}
+
+// normalize lowercases name and turns spaces and underscores into dashes,
+// the separator endoflife.date slugs use.
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(name)
+}
+
+// distance is the optimal string alignment distance between a and b:
+// the Levenshtein distance with adjacent transpositions counted as one edit,
+// so "ngnix" is one edit away from "nginx".
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
@@ -0,0 +1,90 @@
+package resolve
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var testProducts = []string{
+	"go", "kubernetes", "mariadb", "mongodb", "mysql", "nginx", "nodejs",
+	"php", "postgresql", "python", "rails", "redis", "rhel", "ruby",
+}
+
+func TestBest(t *testing.T) {
+	r := New(testProducts, map[string]string{"web": "nginx", "db": "oracle", "pg": "mysql"})
+
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"exact", "nodejs", "nodejs", true},
+		{"case and separators", "Ruby_On Rails", "rails", true},
+		{"default alias", "Node.JS", "nodejs", true},
+		{"custom alias", "web", "nginx", true},
+		{"custom alias overrides default", "pg", "mysql", true},
+		{"alias to unknown product ignored", "db", "", false},
+		{"transposition", "ngnix", "nginx", true},
+		{"one edit", "mysq", "mysql", true},
+		{"prefix", "postgre", "postgresql", true},
+		{"prefix of a long name", "kuber", "kubernetes", true},
+		{"two edits", "nodjss", "", false},
+		{"ambiguous", "ph", "", false},
+		{"unknown", "foobar", "", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.Best(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Best(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r := New(testProducts, nil)
+
+	if got, err := r.Resolve("golang"); err != nil || got != "go" {
+		t.Errorf("Resolve(golang) = %q, %v; want go", got, err)
+	}
+
+	_, err := r.Resolve("ph")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Resolve(ph) error = %v, want a *NotFoundError", err)
+	}
+	if want := []string{"php", "postgresql", "python"}; !reflect.DeepEqual(notFound.Suggestions, want) {
+		t.Errorf("suggestions = %q, want %q", notFound.Suggestions, want)
+	}
+	if want := `unknown product "ph", did you mean: php, postgresql, python?`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	_, err = r.Resolve("foobar")
+	if want := `unknown product "foobar"`; err == nil || err.Error() != want {
+		t.Errorf("Resolve(foobar) error = %v, want %q", err, want)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"nginx", "nginx", 0},
+		{"ngnix", "nginx", 1},
+		{"nodej", "nodejs", 1},
+		{"pyhton", "python", 1},
+		{"redis", "rhel", 4},
+		{"", "go", 2},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package waiver records risk acceptances: findings a team has consciously
+// accepted until an expiry date. Waived findings are reported as ACCEPTED
+// instead of their risk level and do not fail CI gates.
+package waiver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/asafdavid23/eolctl/internal/yamljson"
+	"github.com/asafdavid23/eolctl/pkg/policy"
+)
+
+// Accepted is the level reported for waived findings.
+const Accepted = "ACCEPTED"
+
+// DefaultFile is the waiver file looked up in a scanned project directory.
+const DefaultFile = ".eolctl-waivers.yaml"
+
+// Waiver accepts the risk of findings matching Product, Version and the
+// optional Namespace or Path until Expires. Every field except Reason,
+// Owner and Expires is a glob pattern as in policy overrides.
+type Waiver struct {
+	Product string `json:"product"`
+	// Version matches the installed version or its release cycle, so "3.8"
+	// covers 3.8.10; empty matches any version.
+	Version   string `json:"version,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Reason    string `json:"reason"`
+	Owner     string `json:"owner"`
+	// Expires is the last day the waiver applies, as YYYY-MM-DD.
+	Expires string `json:"expires"`
+
+	expires time.Time
+}
+
+// ExpiresAt returns the moment the waiver stops applying: the end of the
+// Expires day, UTC.
+func (w Waiver) ExpiresAt() time.Time {
+	return w.expires
+}
+
+// Expired reports whether the waiver no longer applies at now.
+func (w Waiver) Expired(now time.Time) bool {
+	return !now.Before(w.expires)
+}
+
+func (w Waiver) String() string {
+	s := w.Product
+	if w.Version != "" {
+		s += " " + w.Version
+	}
+	switch {
+	case w.Namespace != "" && w.Path != "":
+		s += fmt.Sprintf(" (namespace %s, path %s)", w.Namespace, w.Path)
+	case w.Namespace != "":
+		s += fmt.Sprintf(" (namespace %s)", w.Namespace)
+	case w.Path != "":
+		s += fmt.Sprintf(" (path %s)", w.Path)
+	}
+	return s
+}
+
+func (w Waiver) matches(scope policy.Scope, version, cycle string) bool {
+	if !policy.Match(w.Product, scope.Product) {
+		return false
+	}
+	if w.Version != "" && !policy.Match(w.Version, version) && (cycle == "" || !policy.Match(w.Version, cycle)) {
+		return false
+	}
+	if w.Namespace != "" && !policy.Match(w.Namespace, scope.Namespace) {
+		return false
+	}
+	if w.Path != "" && !policy.Match(w.Path, filepath.ToSlash(scope.Path)) {
+		return false
+	}
+	return true
+}
+
+type file struct {
+	Waivers []Waiver `json:"waivers"`
+}
+
+// Set is a loaded list of waivers that remembers which ones matched a
+// finding.
+type Set struct {
+	Path    string
+	waivers []Waiver
+	matched []bool
+}
+
+// Load reads a YAML or JSON waiver file.
+func Load(path string) (*Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waivers: %w", err)
+	}
+
+	var f file
+	if err := yamljson.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse waivers %s: %w", path, err)
+	}
+	for i := range f.Waivers {
+		if err := f.Waivers[i].validate(); err != nil {
+			return nil, fmt.Errorf("invalid waiver %d in %s: %w", i+1, path, err)
+		}
+	}
+	return &Set{Path: path, waivers: f.Waivers, matched: make([]bool, len(f.Waivers))}, nil
+}
+
+func (w *Waiver) validate() error {
+	switch {
+	case w.Product == "":
+		return fmt.Errorf("product is required")
+	case w.Reason == "":
+		return fmt.Errorf("%s: reason is required", w)
+	case w.Owner == "":
+		return fmt.Errorf("%s: owner is required", w)
+	case w.Expires == "":
+		return fmt.Errorf("%s: expires is required", w)
+	}
+	day, err := time.Parse(time.DateOnly, w.Expires)
+	if err != nil {
+		return fmt.Errorf("%s: invalid expires date %q, expected YYYY-MM-DD", w, w.Expires)
+	}
+	w.expires = day.AddDate(0, 0, 1)
+	return nil
+}
+
+// Match returns the first waiver covering a finding for version (in
+// release cycle cycle, if known) in scope. Expired waivers still count as
+// matched, so they are reported as expired rather than unused, but only
+// a waiver in force at now is returned.
+func (s *Set) Match(scope policy.Scope, version, cycle string, now time.Time) (*Waiver, bool) {
+	if s == nil {
+		return nil, false
+	}
+	for i := range s.waivers {
+		if !s.waivers[i].matches(scope, version, cycle) {
+			continue
+		}
+		s.matched[i] = true
+		if !s.waivers[i].Expired(now) {
+			return &s.waivers[i], true
+		}
+	}
+	return nil, false
+}
+
+// Expired returns the waivers that have expired at now.
+func (s *Set) Expired(now time.Time) []Waiver {
+	if s == nil {
+		return nil
+	}
+	var out []Waiver
+	for _, w := range s.waivers {
+		if w.Expired(now) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Unmatched returns the waivers that have not matched any finding so far.
+func (s *Set) Unmatched() []Waiver {
+	if s == nil {
+		return nil
+	}
+	var out []Waiver
+	for i, w := range s.waivers {
+		if !s.matched[i] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package waiver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asafdavid23/eolctl/pkg/policy"
+)
+
+func loadWaivers(t *testing.T, content string) *Set {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return s
+}
+
+const testWaivers = `
+waivers:
+  - product: python
+    version: "3.8"
+    reason: migration planned for Q3
+    owner: data-team
+    expires: 2026-06-30
+  - product: nodejs
+    path: services/legacy/**
+    reason: service is being retired
+    owner: web-team
+    expires: 2026-03-31
+  - product: nginx
+    namespace: ingress-*
+    reason: vendor appliance
+    owner: platform
+    expires: 2025-12-31
+  - product: go
+    version: "1.2?"
+    reason: expired, replaced below
+    owner: platform
+    expires: 2025-01-31
+  - product: go
+    version: "1.2?"
+    reason: toolchain upgrade in progress
+    owner: platform
+    expires: 2026-12-31
+`
+
+func TestSetMatch(t *testing.T) {
+	s := loadWaivers(t, testWaivers)
+	day := func(d string) time.Time {
+		ts, err := time.Parse(time.DateOnly, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	tests := []struct {
+		name       string
+		scope      policy.Scope
+		version    string
+		cycle      string
+		now        time.Time
+		wantReason string
+	}{
+		{"version by cycle", policy.Scope{Product: "python"}, "3.8.10", "3.8", day("2026-01-01"), "migration planned for Q3"},
+		{"cycle only", policy.Scope{Product: "python"}, "3.8", "", day("2026-01-01"), "migration planned for Q3"},
+		{"other cycle", policy.Scope{Product: "python"}, "3.9.1", "3.9", day("2026-01-01"), ""},
+		{"last day in force", policy.Scope{Product: "python"}, "3.8.10", "3.8", day("2026-06-30").Add(23 * time.Hour), "migration planned for Q3"},
+		{"day after expiry", policy.Scope{Product: "python"}, "3.8.10", "3.8", day("2026-07-01"), ""},
+		{"path glob", policy.Scope{Product: "nodejs", Path: "services/legacy/api/package.json"}, "18.20.4", "18", day("2026-01-01"), "service is being retired"},
+		{"path glob does not match", policy.Scope{Product: "nodejs", Path: "services/api/package.json"}, "18.20.4", "18", day("2026-01-01"), ""},
+		{"no path to match", policy.Scope{Product: "nodejs"}, "18.20.4", "18", day("2026-01-01"), ""},
+		{"namespace glob", policy.Scope{Product: "nginx", Namespace: "ingress-public"}, "1.25.3", "1.25", day("2025-12-31"), "vendor appliance"},
+		{"namespace waiver expired", policy.Scope{Product: "nginx", Namespace: "ingress-public"}, "1.25.3", "1.25", day("2026-01-01"), ""},
+		{"skips expired waiver", policy.Scope{Product: "go"}, "1.21.5", "1.21", day("2026-01-01"), "toolchain upgrade in progress"},
+		{"first waiver in force wins", policy.Scope{Product: "go"}, "1.21.5", "1.21", day("2025-01-15"), "expired, replaced below"},
+		{"other product", policy.Scope{Product: "ruby"}, "3.1.4", "3.1", day("2026-01-01"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, ok := s.Match(tt.scope, tt.version, tt.cycle, tt.now)
+			var got string
+			if ok {
+				got = w.Reason
+			}
+			if got != tt.wantReason {
+				t.Errorf("Match() = %q, want %q", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSetExpiredAndUnmatched(t *testing.T) {
+	s := loadWaivers(t, testWaivers)
+	now := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	var expired []string
+	for _, w := range s.Expired(now) {
+		expired = append(expired, w.Reason)
+	}
+	if want := "service is being retired, vendor appliance, expired, replaced below"; strings.Join(expired, ", ") != want {
+		t.Errorf("Expired() = %q, want %q", expired, want)
+	}
+
+	// An expired waiver that matches counts as used, so it is reported as
+	// expired rather than unused.
+	if _, ok := s.Match(policy.Scope{Product: "nginx", Namespace: "ingress-public"}, "1.25.3", "1.25", now); ok {
+		t.Error("Match() returned an expired waiver")
+	}
+	s.Match(policy.Scope{Product: "python"}, "3.8.10", "3.8", now)
+
+	var unmatched []string
+	for _, w := range s.Unmatched() {
+		unmatched = append(unmatched, w.String())
+	}
+	if want := "nodejs (path services/legacy/**), go 1.2?, go 1.2?"; strings.Join(unmatched, ", ") != want {
+		t.Errorf("Unmatched() = %q, want %q", unmatched, want)
+	}
+}
+
+func TestNilSet(t *testing.T) {
+	var s *Set
+	if _, ok := s.Match(policy.Scope{Product: "go"}, "1.22.0", "1.22", time.Now()); ok {
+		t.Error("nil Set matched")
+	}
+	if s.Expired(time.Now()) != nil || s.Unmatched() != nil {
+		t.Error("nil Set returned waivers")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing product", `waivers: [{reason: r, owner: o, expires: 2026-01-01}]`, "product is required"},
+		{"missing reason", `waivers: [{product: go, owner: o, expires: 2026-01-01}]`, "reason is required"},
+		{"missing owner", `waivers: [{product: go, reason: r, expires: 2026-01-01}]`, "owner is required"},
+		{"missing expiry", `waivers: [{product: go, reason: r, owner: o}]`, "expires is required"},
+		{"invalid expiry", `waivers: [{product: go, reason: r, owner: o, expires: 01/02/2026}]`, "invalid expires date"},
+		{"invalid YAML", `waivers: [`, "failed to parse waivers"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFile)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}